@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/brandonc/tfpgen/pkg/restutils"
+	"github.com/brandonc/tfpgen/internal/config"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/mitchellh/cli"
 )
@@ -23,7 +23,12 @@ func (c ExamineCommand) Run(args []string) int {
 		return 2
 	}
 
-	probe := restutils.NewProbe(doc)
+	// Probe rules from an existing configuration are honored
+	probe, err := config.ReadProbeConfig("tfpgen.yaml").NewProbe(doc)
+	if err != nil {
+		fmt.Println(err)
+		return 3
+	}
 	resources := probe.ProbeForResources()
 
 	fmt.Printf("%-32v %-64s %-16s %-16s\n", "Config Name", "Paths", "Limit", "Collection Data Source?")
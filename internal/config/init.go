@@ -2,10 +2,15 @@ package config
 
 import (
 	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
 
 	"github.com/brandonc/tfpgen/pkg/naming"
 	"github.com/brandonc/tfpgen/pkg/restutils"
 	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v2"
 )
 
 // NewTerraformResource translates a probed REST resource into a configuration entity
@@ -21,17 +26,29 @@ func NewTerraformResource(resource *restutils.RESTResource) *TerraformResource {
 		return nil
 	}
 
+	resource.Envelope = resource.ProbeForEnvelope(*mediaType)
+
+	identity := ""
+	if id := resource.ProbeForIdentity(*mediaType); id != nil {
+		identity = id.Attribute
+	}
+
 	if resource.IsCRUD() {
 		return &TerraformResource{
 			TfType:           TfTypeResource,
 			TfTypeNameSuffix: naming.ToHCLName(resource.Name),
 			MediaType:        *mediaType,
+			Identity:         identity,
+			Envelope:         resource.Envelope,
 			Binding: BindingInfo{
 				CreateAction: generateBinding(resource.RESTCreate),
 				ReadAction:   generateBinding(resource.RESTShow),
 				UpdateAction: generateBinding(resource.RESTUpdate),
 				DeleteAction: generateBinding(resource.RESTDelete),
 			},
+			Async:          asyncInfo(resource),
+			NotFound:       NewNotFoundConfig(resource),
+			IdempotencyKey: resource.ProbeForIdempotencyKey(resource.RESTCreate),
 		}
 	}
 
@@ -40,6 +57,8 @@ func NewTerraformResource(resource *restutils.RESTResource) *TerraformResource {
 			TfType:           TfTypeDataSource,
 			TfTypeNameSuffix: naming.ToHCLName(resource.Name),
 			MediaType:        *mediaType,
+			Identity:         identity,
+			Envelope:         resource.Envelope,
 			Binding: BindingInfo{
 				ReadAction: generateBinding(resource.RESTShow),
 			},
@@ -50,6 +69,7 @@ func NewTerraformResource(resource *restutils.RESTResource) *TerraformResource {
 		TfType:           TfTypeDataSource,
 		TfTypeNameSuffix: naming.ToHCLName(resource.Name),
 		MediaType:        *mediaType,
+		Envelope:         resource.Envelope,
 		Binding: BindingInfo{
 			IndexAction: generateBinding(resource.RESTIndex),
 		},
@@ -67,11 +87,167 @@ func generateBinding(action *restutils.RESTAction) *ActionBinding {
 	}
 }
 
+// commonParameters finds the parameters common to most operations of the bound resources.
+// Parameters that carry an API key are configured by their security scheme instead.
+func commonParameters(resources []*restutils.RESTResource, schemes map[string]*SecuritySchemeConfig) []ParameterConfig {
+	result := make([]ParameterConfig, 0)
+	for _, att := range restutils.ProbeForCommonParameters(resources, restutils.CommonParameterThreshold) {
+		if isApiKeyParameter(schemes, att.In, att.Name) {
+			continue
+		}
+		result = append(result, ParameterConfig{
+			Name: att.Name,
+			In:   string(att.In),
+		})
+	}
+	return result
+}
+
+func isApiKeyParameter(schemes map[string]*SecuritySchemeConfig, in restutils.In, name string) bool {
+	for _, scheme := range schemes {
+		if scheme.Type == ApiKeySecurityScheme && restutils.In(scheme.In) == in && restutils.SameParameterName(in, scheme.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// securitySchemes translates the supported OpenAPI security schemes into configuration
+func securitySchemes(doc *openapi3.T) map[string]*SecuritySchemeConfig {
+	result := make(map[string]*SecuritySchemeConfig)
+	if doc.Components.SecuritySchemes == nil {
+		return result
+	}
+
+	for name, ref := range doc.Components.SecuritySchemes {
+		scheme := ref.Value
+		if scheme == nil {
+			continue
+		}
+
+		switch {
+		case scheme.Type == "apiKey":
+			result[name] = &SecuritySchemeConfig{
+				Type: ApiKeySecurityScheme,
+				Name: scheme.Name,
+				In:   scheme.In,
+			}
+		case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer"):
+			result[name] = &SecuritySchemeConfig{Type: TokenSecurityScheme}
+		case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+			result[name] = &SecuritySchemeConfig{Type: BasicSecurityScheme}
+		case scheme.Type == "oauth2" && scheme.Flows != nil && scheme.Flows.ClientCredentials != nil:
+			flow := scheme.Flows.ClientCredentials
+			scopes := make([]string, 0, len(flow.Scopes))
+			for scope := range flow.Scopes {
+				scopes = append(scopes, scope)
+			}
+			sort.Strings(scopes)
+
+			result[name] = &SecuritySchemeConfig{
+				Type:     ClientCredentialsSecurityScheme,
+				TokenURL: flow.TokenURL,
+				Scopes:   scopes,
+			}
+		default:
+			fmt.Printf("warning: security scheme \"%s\" is not supported\n", name)
+		}
+	}
+	return result
+}
+
+// exampleEndpoint is the default endpoint when the spec does not declare an absolute server URL
+const exampleEndpoint = "https://api.example.com/"
+
+// serverEndpoint returns the URL and variables of the first server declared by the spec. A
+// relative server URL is kept as it is, since the host of the API is not known, and the
+// generated provider requires an endpoint to be configured.
+func serverEndpoint(doc *openapi3.T) (string, map[string]*ServerVariableConfig) {
+	if len(doc.Servers) == 0 || doc.Servers[0] == nil || len(doc.Servers[0].URL) == 0 {
+		return exampleEndpoint, nil
+	}
+
+	server := doc.Servers[0]
+	endpoint := server.URL
+	if !IsAbsoluteEndpoint(endpoint) {
+		fmt.Printf("warning: the server URL \"%s\" is relative, so the provider endpoint must be configured unless default_endpoint is set to an absolute URL\n", endpoint)
+	}
+
+	if len(server.Variables) == 0 {
+		return endpoint, nil
+	}
+
+	variables := make(map[string]*ServerVariableConfig, len(server.Variables))
+	for name, variable := range server.Variables {
+		if variable == nil {
+			continue
+		}
+		variables[name] = &ServerVariableConfig{
+			Default:     variable.Default,
+			Enum:        variable.Enum,
+			Description: variable.Description,
+		}
+	}
+	return endpoint, variables
+}
+
+// asyncConfig describes how to wait for the action, if it is asynchronous
+func asyncConfig(resource *restutils.RESTResource, action *restutils.RESTAction) *AsyncConfig {
+	async := resource.ProbeForAsync(action)
+	if async == nil {
+		return nil
+	}
+
+	result := &AsyncConfig{Header: async.Header}
+	if len(async.Header) == 0 && action.Name != restutils.Delete {
+		fmt.Printf("warning: %s %s is asynchronous, but its status URL is not documented\n", action.Method, action.Path)
+	}
+	return result
+}
+
+// asyncInfo describes how to wait for the asynchronous actions of the resource, or is nil if
+// there are none
+func asyncInfo(resource *restutils.RESTResource) *AsyncInfo {
+	result := &AsyncInfo{
+		CreateAction: asyncConfig(resource, resource.RESTCreate),
+		UpdateAction: asyncConfig(resource, resource.RESTUpdate),
+		DeleteAction: asyncConfig(resource, resource.RESTDelete),
+	}
+
+	if result.CreateAction == nil && result.UpdateAction == nil && result.DeleteAction == nil {
+		return nil
+	}
+	return result
+}
+
+// NewNotFoundConfig lists the documented not found status codes of the read and delete
+// actions. Few specs document them, so read removes the resource on 404 Not Found and 410 Gone
+// unless other codes are documented, and delete always treats 404 Not Found as success, since
+// the resource is already gone.
+func NewNotFoundConfig(resource *restutils.RESTResource) *NotFoundConfig {
+	result := &NotFoundConfig{
+		Read:   resource.GetNotFoundStatusCodes(resource.RESTShow),
+		Delete: resource.GetNotFoundStatusCodes(resource.RESTDelete),
+	}
+
+	if len(result.Read) == 0 {
+		result.Read = append(result.Read, restutils.NotFoundStatusCodes...)
+	}
+
+	for _, code := range result.Delete {
+		if code == http.StatusNotFound {
+			return result
+		}
+	}
+	result.Delete = append([]int{http.StatusNotFound}, result.Delete...)
+	return result
+}
+
 func defaultConfig(path string) Config {
 	return Config{
 		Api: ApiConfig{
 			Scheme:          "bearer_token",
-			DefaultEndpoint: "https://api.example.com/",
+			DefaultEndpoint: exampleEndpoint,
 		},
 		Provider: ProviderConfig{
 			Name:             "yourname/example",
@@ -84,6 +260,29 @@ func defaultConfig(path string) Config {
 	}
 }
 
+// ReadProbeConfig reads the probe section of an existing configuration file, so that probe
+// rules can be refined and init run again. Only the probe section is read, since the rest of
+// the file may describe an older spec. If the file does not exist or the probe section cannot
+// be read, the default is returned.
+func ReadProbeConfig(path string) *ProbeConfig {
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &ProbeConfig{}
+	}
+
+	existing := struct {
+		Probe ProbeConfig `yaml:"probe"`
+	}{}
+	if err == nil {
+		err = yaml.Unmarshal(raw, &existing)
+	}
+	if err != nil {
+		fmt.Printf("warning: ignoring the probe section of %s: %s\n", path, err)
+		return &ProbeConfig{}
+	}
+	return &existing.Probe
+}
+
 func InitConfig(path string) error {
 	doc, err := openapi3.NewLoader().LoadFromFile(path)
 
@@ -91,17 +290,31 @@ func InitConfig(path string) error {
 		return fmt.Errorf("invalid openapi3 spec: %w", err)
 	}
 
-	probe := restutils.NewProbe(doc)
+	probeConfig := ReadProbeConfig("tfpgen.yaml")
+	probe, err := probeConfig.NewProbe(doc)
+	if err != nil {
+		return err
+	}
 	resources := probe.ProbeForResources()
 
 	cfg := defaultConfig(path)
+	cfg.Probe = *probeConfig
 
+	bound := make([]*restutils.RESTResource, 0, len(resources))
 	for name, resource := range resources {
 		if tfResource := NewTerraformResource(resource); tfResource != nil {
 			cfg.Output[name] = tfResource
+			bound = append(bound, resource)
 		}
 	}
 
+	if schemes := securitySchemes(doc); len(schemes) > 0 {
+		cfg.Api.Scheme = ""
+		cfg.Api.SecuritySchemes = schemes
+	}
+	cfg.Api.Parameters = commonParameters(bound, cfg.Api.SecuritySchemes)
+	cfg.Api.DefaultEndpoint, cfg.Api.ServerVariables = serverEndpoint(doc)
+
 	if err = cfg.Write("tfpgen.yaml"); err != nil {
 		return err
 	}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/brandonc/tfpgen/pkg/restutils"
@@ -41,6 +44,10 @@ func Test_NewTerraformResource(t *testing.T) {
 			t.Error("Expected name suffix to be \"boards\"")
 		}
 
+		if tfr.Identity != "id" {
+			t.Errorf("Expected identity to be \"id\", got \"%s\"", tfr.Identity)
+		}
+
 		if tfr.Binding.CreateAction == nil {
 			t.Error("Expected boards CreateAction to not be nil")
 		}
@@ -56,5 +63,238 @@ func Test_NewTerraformResource(t *testing.T) {
 		if tfr.Binding.DeleteAction == nil {
 			t.Error("Expected boards DeleteAction to not be nil")
 		}
+
+		if tfr.Async != nil {
+			t.Error("Expected boards to have no asynchronous actions")
+		}
+
+		if tfr.NotFound == nil || !reflect.DeepEqual(tfr.NotFound.Delete, []int{404}) {
+			t.Errorf("Expected boards delete to treat 404 as success, got %#v", tfr.NotFound)
+		}
+
+		if tfr.NotFound == nil || !reflect.DeepEqual(tfr.NotFound.Read, []int{404}) {
+			t.Errorf("Expected boards read to remove the resource on the documented 404, got %#v", tfr.NotFound)
+		}
+	})
+
+	t.Run("Undocumented not found responses", func(t *testing.T) {
+		doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/patch.yaml")
+
+		if err != nil {
+			t.Fatalf("invalid fixture: %s\n", err)
+		}
+
+		probe := restutils.NewProbe(doc)
+		notes, ok := probe.ProbeForResources()["Notes"]
+		if !ok {
+			t.Fatal("Expected \"Notes\" resource")
+		}
+
+		tfr := NewTerraformResource(notes)
+		if tfr == nil || tfr.NotFound == nil {
+			t.Fatal("Expected terraform resource with a not found config")
+		}
+
+		if !reflect.DeepEqual(tfr.NotFound.Read, []int{404, 410}) || !reflect.DeepEqual(tfr.NotFound.Delete, []int{404}) {
+			t.Errorf("Expected notes read to remove the resource on 404 and 410, got %#v", tfr.NotFound)
+		}
+	})
+
+	t.Run("Asynchronous resource", func(t *testing.T) {
+		doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/async.yaml")
+
+		if err != nil {
+			t.Fatalf("invalid fixture: %s\n", err)
+		}
+
+		probe := restutils.NewProbe(doc)
+		clusters, ok := probe.ProbeForResources()["Clusters"]
+		if !ok {
+			t.Fatal("Expected \"Clusters\" resource")
+		}
+
+		tfr := NewTerraformResource(clusters)
+		if tfr == nil || tfr.Async == nil {
+			t.Fatal("Expected asynchronous actions")
+		}
+
+		if tfr.Async.CreateAction == nil || tfr.Async.CreateAction.Header != "Operation-Location" {
+			t.Errorf("Expected create to be polled with the Operation-Location header, got %#v", tfr.Async.CreateAction)
+		}
+
+		if tfr.Async.UpdateAction != nil {
+			t.Error("Expected update to be synchronous")
+		}
+
+		if tfr.Async.DeleteAction == nil || tfr.Async.DeleteAction.StatusHeader() != "Location" {
+			t.Errorf("Expected delete to be polled with the default header, got %#v", tfr.Async.DeleteAction)
+		}
+	})
+}
+
+func Test_commonParameters(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/openapi3/nomad.yaml")
+
+	if err != nil {
+		t.Fatalf("invalid fixture: %s\n", err)
+	}
+
+	probe := restutils.NewProbe(doc)
+	resources := make([]*restutils.RESTResource, 0)
+	for _, resource := range probe.ProbeForResources() {
+		resources = append(resources, resource)
+	}
+
+	schemes := securitySchemes(doc)
+	parameters := commonParameters(resources, schemes)
+
+	found := false
+	for _, parameter := range parameters {
+		if parameter.Name == "region" {
+			found = true
+			if parameter.In != "query" {
+				t.Errorf("Expected region to be in query, got %s", parameter.In)
+			}
+		}
+	}
+
+	if !found {
+		t.Errorf("Expected region to be a common parameter, got %v", parameters)
+	}
+
+	for _, parameter := range parameters {
+		if parameter.Name == "X-Nomad-Token" {
+			t.Error("Expected the X-Nomad-Token API key to be excluded from common parameters")
+		}
+	}
+
+	cfg := Config{Api: ApiConfig{Parameters: parameters}}
+	if _, err := cfg.Api.ParameterBindings(); err != nil {
+		t.Errorf("Expected common parameters to be valid bindings: %s", err)
+	}
+}
+
+func Test_securitySchemes(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/security.yaml")
+
+	if err != nil {
+		t.Fatalf("invalid fixture: %s\n", err)
+	}
+
+	schemes := securitySchemes(doc)
+
+	expected := map[string]SecurityScheme{
+		"BearerAuth": TokenSecurityScheme,
+		"Basic":      BasicSecurityScheme,
+		"ApiKey":     ApiKeySecurityScheme,
+		"OAuth2":     ClientCredentialsSecurityScheme,
+	}
+
+	if len(schemes) != len(expected) {
+		t.Errorf("Expected %d supported security schemes, got %d", len(expected), len(schemes))
+	}
+
+	for name, schemeType := range expected {
+		scheme, ok := schemes[name]
+		if !ok {
+			t.Errorf("Expected security scheme %s", name)
+			continue
+		}
+
+		if scheme.Type != schemeType {
+			t.Errorf("Expected security scheme %s to be %s, got %s", name, schemeType, scheme.Type)
+		}
+	}
+
+	if apiKey := schemes["ApiKey"]; apiKey != nil && (apiKey.Name != "api_key" || apiKey.In != "query") {
+		t.Errorf("Expected ApiKey to be sent as the api_key query parameter, got %s %s", apiKey.In, apiKey.Name)
+	}
+
+	if oauth := schemes["OAuth2"]; oauth != nil {
+		if oauth.TokenURL != "https://auth.example.com/oauth/token" {
+			t.Errorf("Unexpected OAuth2 token URL %s", oauth.TokenURL)
+		}
+		if len(oauth.Scopes) != 2 || oauth.Scopes[0] != "widgets:read" {
+			t.Errorf("Expected sorted OAuth2 scopes, got %v", oauth.Scopes)
+		}
+	}
+
+	cfg := ApiConfig{SecuritySchemes: schemes}
+	if _, err := cfg.Security(); err != nil {
+		t.Errorf("Expected security schemes to be valid: %s", err)
+	}
+}
+
+func Test_serverEndpoint(t *testing.T) {
+	t.Run("server variables", func(t *testing.T) {
+		doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/openapi3/nomad.yaml")
+
+		if err != nil {
+			t.Fatalf("invalid fixture: %s\n", err)
+		}
+
+		endpoint, variables := serverEndpoint(doc)
+		if endpoint != "{scheme}://{address}:{port}/v1" {
+			t.Errorf("Expected the first server URL, got %s", endpoint)
+		}
+
+		if len(variables) != 3 {
+			t.Fatalf("Expected 3 server variables, got %d", len(variables))
+		}
+
+		if scheme := variables["scheme"]; scheme.Default != "https" || len(scheme.Enum) != 2 {
+			t.Errorf("Unexpected scheme variable %#v", scheme)
+		}
+	})
+
+	t.Run("relative server URL", func(t *testing.T) {
+		doc := &openapi3.T{Servers: openapi3.Servers{{URL: "/v3"}}}
+
+		if endpoint, _ := serverEndpoint(doc); endpoint != "/v3" {
+			t.Errorf("Expected the relative server URL to be kept, got %s", endpoint)
+		}
+	})
+
+	t.Run("no servers", func(t *testing.T) {
+		if endpoint, variables := serverEndpoint(&openapi3.T{}); endpoint != exampleEndpoint || variables != nil {
+			t.Errorf("Expected the example endpoint, got %s %v", endpoint, variables)
+		}
+	})
+}
+
+func Test_ReadProbeConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("missing file", func(t *testing.T) {
+		if probe := ReadProbeConfig(filepath.Join(dir, "missing.yaml")); probe.Prefix != nil || len(probe.Rules) != 0 {
+			t.Errorf("expected the default probe config, got %#v", probe)
+		}
+	})
+
+	t.Run("only the probe section is read", func(t *testing.T) {
+		path := filepath.Join(dir, "outdated.yaml")
+		content := "probe:\n  prefix: \"\"\n  rules:\n  - pattern: ^/v3/boards\n    resource: Boards\noutput:\n  boards:\n    binding: not a binding\n"
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		probe := ReadProbeConfig(path)
+		if probe.Prefix == nil || *probe.Prefix != "" {
+			t.Errorf("expected an empty prefix, got %v", probe.Prefix)
+		}
+		if len(probe.Rules) != 1 || probe.Rules[0].Resource != "Boards" {
+			t.Errorf("expected the Boards rule, got %#v", probe.Rules)
+		}
+	})
+
+	t.Run("invalid probe section", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid.yaml")
+		if err := os.WriteFile(path, []byte("probe:\n  rules: not a list\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		if probe := ReadProbeConfig(path); probe.Prefix != nil || len(probe.Rules) != 0 {
+			t.Errorf("expected the default probe config, got %#v", probe)
+		}
 	})
 }
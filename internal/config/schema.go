@@ -3,9 +3,13 @@ package config
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/brandonc/tfpgen/pkg/naming"
 	"github.com/brandonc/tfpgen/pkg/restutils"
+	"github.com/getkin/kin-openapi/openapi3"
 	"gopkg.in/yaml.v2"
 )
 
@@ -23,6 +27,15 @@ const (
 const (
 	// TokenSecurityScheme describes a bearer token security scheme
 	TokenSecurityScheme SecurityScheme = "bearer_token"
+
+	// ApiKeySecurityScheme describes an API key sent as a header, query, or cookie parameter
+	ApiKeySecurityScheme SecurityScheme = "api_key"
+
+	// BasicSecurityScheme describes HTTP basic authentication
+	BasicSecurityScheme SecurityScheme = "basic"
+
+	// ClientCredentialsSecurityScheme describes the OAuth2 client credentials flow
+	ClientCredentialsSecurityScheme SecurityScheme = "oauth2_client_credentials"
 )
 
 // ActionBinding is the config section that describes an openAPI path/verb binding
@@ -40,19 +53,397 @@ type BindingInfo struct {
 	IndexAction  *ActionBinding `yaml:"index,omitempty"`
 }
 
+// ParameterConfig is the config section that exposes a query, header, or cookie parameter
+// as a resource or provider attribute. The generated client sends the attribute value as that
+// parameter.
+type ParameterConfig struct {
+	Name string `yaml:"name"`
+	In   string `yaml:"in"`
+}
+
 // TerraformResource is either a terraform resource or data source that can be interacted with using terraform
 type TerraformResource struct {
 	TfTypeNameSuffix string      `yaml:"tf_type_name_suffix"`
 	TfType           TfType      `yaml:"tf_type"`
 	MediaType        string      `yaml:"media_type"`
 	Binding          BindingInfo `yaml:"binding"`
+
+	// Identity is the name of the response attribute that identifies the resource. Its value
+	// is used as the last path parameter of the read action. By default, it is determined by
+	// matching that path parameter with a read response attribute.
+	Identity string `yaml:"identity,omitempty"`
+
+	// Parameters are the query, header, or cookie parameters exposed as attributes. Path
+	// parameters are always exposed.
+	Parameters []ParameterConfig `yaml:"parameters,omitempty"`
+
+	// Async describes the actions that respond with 202 Accepted and complete later
+	Async *AsyncInfo `yaml:"async,omitempty"`
+
+	// Timeouts adds a timeouts attribute to the resource, so that the duration of each action
+	// can be configured. An empty section uses the default timeouts.
+	Timeouts *TimeoutsConfig `yaml:"timeouts,omitempty"`
+
+	// NotFound lists the response status codes that indicate the resource no longer exists. By
+	// default, they are the documented not found responses of each action, and 404 Not Found
+	// and 410 Gone for read if none are documented.
+	NotFound *NotFoundConfig `yaml:"not_found,omitempty"`
+
+	// IdempotencyKey is the name of the header, like "Idempotency-Key", that identifies each
+	// create call so that it can safely be retried. By default, it is the idempotency key header
+	// declared by the create action, if any. The key is kept in the idempotency_key attribute,
+	// which can be configured to keep it the same when a failed apply is applied again.
+	IdempotencyKey string `yaml:"idempotency_key,omitempty"`
+
+	// Consistency makes create and update wait for the API to be consistent, for APIs that do
+	// not read their own writes immediately
+	Consistency *ConsistencyConfig `yaml:"consistency,omitempty"`
+
+	// Envelope is a JSON pointer to the resource within request and response bodies, like
+	// "/data". By default, it is the API envelope.
+	Envelope string `yaml:"envelope,omitempty"`
+
+	// Attributes map attributes to nested properties of the resource, which flattens or
+	// renames them
+	Attributes []AttributeConfig `yaml:"attributes,omitempty"`
+}
+
+// AttributeConfig is the config section that maps an attribute to the property at a JSON
+// pointer within the resource, like "/spec/template/metadata/labels". The property is exposed
+// as the attribute instead of within the attributes of the objects that contain it.
+type AttributeConfig struct {
+	Name    string `yaml:"name"`
+	Pointer string `yaml:"pointer"`
+}
+
+// AttributeMappings validates the attribute config sections of the resource
+func (r *TerraformResource) AttributeMappings() ([]restutils.AttributeMapping, error) {
+	result := make([]restutils.AttributeMapping, 0, len(r.Attributes))
+	names := make(map[string]bool)
+	for _, attribute := range r.Attributes {
+		if !naming.ValidHCLIdentifier(attribute.Name) {
+			return nil, fmt.Errorf("attribute \"%s\" is not a valid name", attribute.Name)
+		}
+		if !strings.HasPrefix(attribute.Pointer, "/") {
+			return nil, fmt.Errorf("attribute %s pointer \"%s\" is not a JSON pointer, which must start with /", attribute.Name, attribute.Pointer)
+		}
+		if names[attribute.Name] {
+			return nil, fmt.Errorf("attribute %s is mapped more than once", attribute.Name)
+		}
+		names[attribute.Name] = true
+
+		result = append(result, restutils.AttributeMapping{Name: attribute.Name, Pointer: attribute.Pointer})
+	}
+	return result, nil
+}
+
+// Defaults of the consistency config section
+const (
+	DefaultConsistencyInterval = "2s"
+	DefaultConsistencyTimeout  = "2m"
+)
+
+// ConsistencyConfig is the config section that describes how to wait for an eventually
+// consistent API. After create and update, the resource is read until it is found and the
+// Attributes, if any, match the plan, or until the Timeout elapses.
+type ConsistencyConfig struct {
+	// Attributes are the API names of the attributes that must match the plan
+	Attributes []string `yaml:"attributes,omitempty"`
+
+	// Interval is the time between reads, by default 2s
+	Interval string `yaml:"interval,omitempty"`
+
+	// Timeout is the longest time to wait, by default 2m
+	Timeout string `yaml:"timeout,omitempty"`
+}
+
+// Durations returns the interval between reads and the timeout of the wait
+func (c *ConsistencyConfig) Durations() (time.Duration, time.Duration, error) {
+	values := []string{c.Interval, c.Timeout}
+	defaults := []string{DefaultConsistencyInterval, DefaultConsistencyTimeout}
+
+	result := make([]time.Duration, len(values))
+	for i, value := range values {
+		if len(value) == 0 {
+			value = defaults[i]
+		}
+
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid consistency duration: %w", err)
+		}
+		if duration <= 0 {
+			return 0, 0, fmt.Errorf("invalid consistency duration: %s is not positive", value)
+		}
+		result[i] = duration
+	}
+
+	if result[0] > result[1] {
+		return 0, 0, fmt.Errorf("the consistency interval %s is longer than the timeout %s", result[0], result[1])
+	}
+	return result[0], result[1], nil
+}
+
+// NotFoundConfig is the config section that lists the response status codes that indicate the
+// resource was deleted outside of terraform. When read responds with one of them, the resource
+// is removed from state so that it is created again. When delete responds with one of them,
+// the resource is already deleted.
+type NotFoundConfig struct {
+	Read   []int `yaml:"read"`
+	Delete []int `yaml:"delete"`
+}
+
+// Validate checks that each not found status code is a client error
+func (n *NotFoundConfig) Validate() error {
+	for _, code := range append(append([]int{}, n.Read...), n.Delete...) {
+		if code < 400 || code > 499 {
+			return fmt.Errorf("invalid not found status code %d: must be a client error", code)
+		}
+	}
+	return nil
+}
+
+// DefaultTimeout is the default duration of each resource action that has a timeout
+const DefaultTimeout = "20m"
+
+// TimeoutsConfig is the config section that sets the default duration of each resource action,
+// like "20m"
+type TimeoutsConfig struct {
+	Create string `yaml:"create,omitempty"`
+	Read   string `yaml:"read,omitempty"`
+	Update string `yaml:"update,omitempty"`
+	Delete string `yaml:"delete,omitempty"`
+}
+
+// Default returns the default duration of the action
+func (t *TimeoutsConfig) Default(action restutils.RESTPseudonym) (time.Duration, error) {
+	var value string
+	switch action {
+	case restutils.Create:
+		value = t.Create
+	case restutils.Show:
+		value = t.Read
+	case restutils.Update:
+		value = t.Update
+	case restutils.Delete:
+		value = t.Delete
+	default:
+		return 0, fmt.Errorf("the %s action has no timeout", action)
+	}
+
+	if len(value) == 0 {
+		value = DefaultTimeout
+	}
+
+	result, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s timeout: %w", action, err)
+	}
+	if result <= 0 {
+		return 0, fmt.Errorf("invalid %s timeout: %s is not positive", action, value)
+	}
+	return result, nil
+}
+
+// AsyncInfo is the config section that describes how to wait for each asynchronous action
+type AsyncInfo struct {
+	CreateAction *AsyncConfig `yaml:"create,omitempty"`
+	UpdateAction *AsyncConfig `yaml:"update,omitempty"`
+	DeleteAction *AsyncConfig `yaml:"delete,omitempty"`
+}
+
+// DefaultPollInterval is the time between polls of an asynchronous operation status
+const DefaultPollInterval = "5s"
+
+// AsyncConfig is the config section that describes how to wait for an action that responds
+// with 202 Accepted. The status URL is taken from the response header, or from the response
+// body if the header is missing. Without either, only delete can be waited for, by polling the
+// resource until it is gone.
+type AsyncConfig struct {
+	// Header is the response header with the status URL, by default Location
+	Header string `yaml:"header,omitempty"`
+
+	// URLPointer is a JSON pointer to the status URL in the response body
+	URLPointer string `yaml:"url_pointer,omitempty"`
+
+	// StatusPointer is a JSON pointer to the status value in the status response. The action
+	// is complete when the value is one of Success, and has failed when it is one of Failure.
+	// If empty, the action is complete when the status response is not 202 Accepted.
+	StatusPointer string   `yaml:"status_pointer,omitempty"`
+	Success       []string `yaml:"success,omitempty"`
+	Failure       []string `yaml:"failure,omitempty"`
+
+	// Interval is the time between polls, like "5s", unless the status response specifies it
+	// with a Retry-After header
+	Interval string `yaml:"interval,omitempty"`
+}
+
+// StatusHeader returns the response header with the status URL
+func (a *AsyncConfig) StatusHeader() string {
+	if len(a.Header) == 0 {
+		return "Location"
+	}
+	return a.Header
+}
+
+// PollInterval returns the time between polls
+func (a *AsyncConfig) PollInterval() (time.Duration, error) {
+	interval := a.Interval
+	if len(interval) == 0 {
+		interval = DefaultPollInterval
+	}
+	return time.ParseDuration(interval)
+}
+
+// Validate ensures that the pointers, terminal conditions, and interval are valid
+func (a *AsyncConfig) Validate() error {
+	for _, pointer := range []string{a.URLPointer, a.StatusPointer} {
+		if len(pointer) > 0 && !strings.HasPrefix(pointer, "/") {
+			return fmt.Errorf("\"%s\" is not a JSON pointer, which must start with /", pointer)
+		}
+	}
+
+	if len(a.StatusPointer) > 0 && len(a.Success) == 0 {
+		return fmt.Errorf("status_pointer requires at least one success value")
+	}
+	if len(a.StatusPointer) == 0 && (len(a.Success) > 0 || len(a.Failure) > 0) {
+		return fmt.Errorf("success and failure values require a status_pointer")
+	}
+
+	if _, err := a.PollInterval(); err != nil {
+		return fmt.Errorf("invalid interval: %w", err)
+	}
+	return nil
+}
+
+// SecuritySchemeConfig is the config section that describes how the provider authenticates
+// using one of the API security schemes
+type SecuritySchemeConfig struct {
+	Type SecurityScheme `yaml:"type"`
+
+	// Name is the name of the api_key parameter
+	Name string `yaml:"name,omitempty"`
+
+	// In is where the api_key parameter is sent: header, query, or cookie
+	In string `yaml:"in,omitempty"`
+
+	// TokenURL is the oauth2_client_credentials token endpoint
+	TokenURL string `yaml:"token_url,omitempty"`
+
+	// Scopes are the oauth2_client_credentials scopes requested by default
+	Scopes []string `yaml:"scopes,omitempty"`
 }
 
 // ProviderConfig is the container for api client configuration. The provider will generate an
 // API client to use with provider.
 type ApiConfig struct {
-	Scheme          SecurityScheme `yaml:"scheme"`
+	// Scheme is a single bearer_token, basic, or oauth2_client_credentials security scheme. It
+	// is ignored if SecuritySchemes are configured.
+	Scheme          SecurityScheme `yaml:"scheme,omitempty"`
 	DefaultEndpoint string         `yaml:"default_endpoint"`
+
+	// SecuritySchemes are keyed by the name of the OpenAPI security scheme. Each produces
+	// sensitive provider attributes for its credentials.
+	SecuritySchemes map[string]*SecuritySchemeConfig `yaml:"security_schemes,omitempty"`
+
+	// Parameters are the query, header, or cookie parameters common to most operations. They
+	// are exposed as provider attributes and sent with every operation that declares them. A
+	// resource that exposes the same parameter as an attribute overrides the provider value.
+	Parameters []ParameterConfig `yaml:"parameters,omitempty"`
+
+	// ServerVariables are the variables of the default endpoint, like {region} in
+	// https://{region}.api.example.com/, keyed by name. Each is exposed as a provider attribute
+	// and substituted into the endpoint.
+	ServerVariables map[string]*ServerVariableConfig `yaml:"server_variables,omitempty"`
+
+	// Envelope is a JSON pointer to the resource within request and response bodies, like
+	// "/data", for APIs that wrap every resource. A resource envelope overrides it.
+	Envelope string `yaml:"envelope,omitempty"`
+
+	// RetryStatusCodes are retried by operations that document none of the transient status
+	// codes, like 429 and 503. By default, only documented status codes are retried.
+	RetryStatusCodes []int `yaml:"retry_status_codes,omitempty"`
+}
+
+// DefaultRetryStatusCodes returns the status codes retried by operations that document none of
+// the transient status codes
+func (a *ApiConfig) DefaultRetryStatusCodes() ([]int, error) {
+	for _, code := range a.RetryStatusCodes {
+		if code < 400 || code > 599 {
+			return nil, fmt.Errorf("retry status code %d is not a client or server error", code)
+		}
+	}
+	return a.RetryStatusCodes, nil
+}
+
+// ResourceEnvelope returns the JSON pointer to the resource within request and response bodies,
+// or an empty string if the bodies are the resource
+func (a *ApiConfig) ResourceEnvelope(resource *TerraformResource) (string, error) {
+	envelope := a.Envelope
+	if len(resource.Envelope) > 0 {
+		envelope = resource.Envelope
+	}
+
+	if len(envelope) > 0 && !strings.HasPrefix(envelope, "/") {
+		return "", fmt.Errorf("envelope \"%s\" is not a JSON pointer, which must start with /", envelope)
+	}
+	return envelope, nil
+}
+
+// IsAbsoluteEndpoint reports whether an endpoint includes the scheme and host of the API, rather
+// than only the path of a relative server URL
+func IsAbsoluteEndpoint(endpoint string) bool {
+	return strings.Contains(endpoint, "://")
+}
+
+// ServerVariableConfig is the config section that describes a variable of the endpoint URL
+type ServerVariableConfig struct {
+	Default     string   `yaml:"default,omitempty"`
+	Enum        []string `yaml:"enum,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+}
+
+// serverVariablePattern matches the variables of a server URL, like {region}
+var serverVariablePattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// Variables validates the server variables and returns their names, ordered by their position
+// in the default endpoint. Every variable in the default endpoint must be configured.
+func (a *ApiConfig) Variables() ([]string, error) {
+	result := make([]string, 0, len(a.ServerVariables))
+	for _, match := range serverVariablePattern.FindAllStringSubmatch(a.DefaultEndpoint, -1) {
+		name := match[1]
+		variable, ok := a.ServerVariables[name]
+		if !ok || variable == nil {
+			return nil, fmt.Errorf("default_endpoint variable %s is not defined in server_variables", name)
+		}
+
+		if len(variable.Enum) > 0 && len(variable.Default) > 0 {
+			found := false
+			for _, value := range variable.Enum {
+				found = found || value == variable.Default
+			}
+			if !found {
+				return nil, fmt.Errorf("server variable %s default \"%s\" is not one of its enum values", name, variable.Default)
+			}
+		}
+
+		seen := false
+		for _, existing := range result {
+			seen = seen || existing == name
+		}
+		if !seen {
+			result = append(result, name)
+		}
+	}
+
+	if len(result) != len(a.ServerVariables) {
+		for name := range a.ServerVariables {
+			if !strings.Contains(a.DefaultEndpoint, "{"+name+"}") {
+				return nil, fmt.Errorf("server variable %s does not appear in default_endpoint", name)
+			}
+		}
+	}
+	return result, nil
 }
 
 // ProviderConfig is the container for provider configuration.
@@ -75,12 +466,177 @@ type ProviderConfig struct {
 
 	// PackageName is the name of the go package to use for the provider, default is "provider"
 	PackageName string `yaml:"package_name"`
+
+	// Env maps provider attribute names to the environment variables they are read from when
+	// not configured. By default, the endpoint is read from <PROVIDERNAME>_ENDPOINT, the api_token
+	// from <PROVIDERNAME>_TOKEN, and other attributes from <PROVIDERNAME>_<ATTRIBUTE>. An empty
+	// name disables the environment variable.
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// Attributes are additional provider settings
+	Attributes []ProviderAttribute `yaml:"attributes,omitempty"`
+}
+
+// ProviderAttributeType is the type of a custom provider attribute
+type ProviderAttributeType string
+
+const (
+	ProviderAttributeString ProviderAttributeType = "string"
+	ProviderAttributeBool   ProviderAttributeType = "bool"
+	ProviderAttributeInt    ProviderAttributeType = "int"
+	ProviderAttributeFloat  ProviderAttributeType = "float"
+	ProviderAttributeList   ProviderAttributeType = "list"
+)
+
+// ClientOption is a generated client setting that a custom provider attribute configures
+type ClientOption string
+
+const (
+	// InsecureSkipVerifyOption disables TLS certificate verification. The attribute must be a bool.
+	InsecureSkipVerifyOption ClientOption = "insecure_skip_verify"
+
+	// TimeoutOption limits the duration of each request. The attribute must be a duration string,
+	// like "30s".
+	TimeoutOption ClientOption = "timeout"
+
+	// MaxRetriesOption limits how many times a request is retried after a transient failure.
+	// The attribute must be an int.
+	MaxRetriesOption ClientOption = "max_retries"
+)
+
+// ProviderAttribute is the config section that adds a provider setting. Every setting is
+// available to the generated client, which can also send it with every request or apply it
+// as a client option.
+type ProviderAttribute struct {
+	Name        string                `yaml:"name"`
+	Type        ProviderAttributeType `yaml:"type,omitempty"`
+	Description string                `yaml:"description,omitempty"`
+	Sensitive   bool                  `yaml:"sensitive,omitempty"`
+
+	// Default is used when the attribute is neither configured nor set in the environment
+	Default interface{} `yaml:"default,omitempty"`
+
+	// Env is the environment variable the attribute is read from. By default, it is determined
+	// like the other provider attributes. An empty name disables the environment variable.
+	Env *string `yaml:"env,omitempty"`
+
+	// In and Parameter send the value with every request as a header, query, or cookie parameter
+	In        string `yaml:"in,omitempty"`
+	Parameter string `yaml:"parameter,omitempty"`
+
+	// Option applies the value to the client
+	Option ClientOption `yaml:"option,omitempty"`
+}
+
+// AttributeType returns the attribute type, which is a string by default
+func (a *ProviderAttribute) AttributeType() ProviderAttributeType {
+	if len(a.Type) == 0 {
+		return ProviderAttributeString
+	}
+	return a.Type
+}
+
+// Validate ensures that the attribute type, default, parameter, and option are consistent
+func (a *ProviderAttribute) Validate() error {
+	if !naming.ValidHCLIdentifier(a.Name) {
+		return fmt.Errorf("provider attribute \"%s\" is not a valid name", a.Name)
+	}
+
+	attributeType := a.AttributeType()
+	valid := true
+	switch attributeType {
+	case ProviderAttributeString:
+		_, valid = a.Default.(string)
+	case ProviderAttributeBool:
+		_, valid = a.Default.(bool)
+	case ProviderAttributeInt:
+		_, valid = a.Default.(int)
+	case ProviderAttributeFloat:
+		switch a.Default.(type) {
+		case int, float64:
+		default:
+			valid = false
+		}
+	case ProviderAttributeList:
+		var items []interface{}
+		if items, valid = a.Default.([]interface{}); valid {
+			for _, item := range items {
+				if _, ok := item.(string); !ok {
+					valid = false
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("provider attribute %s has unsupported type \"%s\"", a.Name, attributeType)
+	}
+
+	if a.Default != nil && !valid {
+		return fmt.Errorf("provider attribute %s default must be a %s", a.Name, attributeType)
+	}
+
+	if len(a.In) > 0 || len(a.Parameter) > 0 {
+		in := restutils.In(strings.ToLower(a.In))
+		if len(a.Parameter) == 0 || (in != restutils.InHeader && in != restutils.InQuery && in != restutils.InCookie) {
+			return fmt.Errorf("provider attribute %s must have a parameter name and be in header, query, or cookie", a.Name)
+		}
+		if attributeType == ProviderAttributeList {
+			return fmt.Errorf("provider attribute %s is a list, which cannot be sent as a parameter", a.Name)
+		}
+	}
+
+	switch a.Option {
+	case "":
+	case InsecureSkipVerifyOption:
+		if attributeType != ProviderAttributeBool {
+			return fmt.Errorf("provider attribute %s must be a bool to set the %s option", a.Name, a.Option)
+		}
+	case TimeoutOption:
+		if attributeType != ProviderAttributeString {
+			return fmt.Errorf("provider attribute %s must be a string to set the %s option", a.Name, a.Option)
+		}
+	case MaxRetriesOption:
+		if attributeType != ProviderAttributeInt {
+			return fmt.Errorf("provider attribute %s must be an int to set the %s option", a.Name, a.Option)
+		}
+	default:
+		return fmt.Errorf("provider attribute %s has unsupported option \"%s\"", a.Name, a.Option)
+	}
+
+	return nil
+}
+
+// ProbeRule is the config section that assigns the operations of matching paths to a resource
+// before any resource names or actions are guessed.
+type ProbeRule struct {
+	// Pattern is a regular expression matched against each OpenAPI path
+	Pattern string `yaml:"pattern"`
+
+	// Resource is the resource name, which may refer to pattern capture groups like "${1}"
+	Resource string `yaml:"resource"`
+
+	// Action is one of create, show (or read), index, update, or delete. If omitted, only the
+	// resource name is assigned and the action is determined automatically.
+	Action string `yaml:"action,omitempty"`
+
+	// Method restricts the rule to a single HTTP method
+	Method string `yaml:"method,omitempty"`
+}
+
+// ProbeConfig is the config section that influences how tfpgen init discovers resources
+type ProbeConfig struct {
+	// Prefix is removed from paths before resource names are derived from them. By default,
+	// the path segments all paths have in common are removed. An empty prefix removes nothing.
+	Prefix *string `yaml:"prefix,omitempty"`
+
+	// Rules are applied in order, before heuristics.
+	Rules []ProbeRule `yaml:"rules,omitempty"`
 }
 
 // Config is the top level configuration schema
 type Config struct {
 	Api      ApiConfig                     `yaml:"api"`
 	Provider ProviderConfig                `yaml:"provider"`
+	Probe    ProbeConfig                   `yaml:"probe,omitempty"`
 	Filename string                        `yaml:"specfile"`
 	Output   map[string]*TerraformResource `yaml:"output"`
 }
@@ -118,6 +674,51 @@ func (p *ProviderConfig) ProviderName() string {
 	return parts[1]
 }
 
+// envVarName converts a name to an upper case environment variable name
+func envVarName(name string) string {
+	return strings.ToUpper(naming.ToHCLName(name))
+}
+
+// EnvVar returns the name of the environment variable the provider attribute is read from, or
+// an empty string if it is not read from the environment.
+func (p *ProviderConfig) EnvVar(attribute string) string {
+	if name, ok := p.Env[attribute]; ok {
+		return name
+	}
+
+	for _, custom := range p.Attributes {
+		if custom.Name == attribute && custom.Env != nil {
+			return *custom.Env
+		}
+	}
+
+	prefix := envVarName(p.ProviderName())
+	switch attribute {
+	case "endpoint":
+		return prefix + "_ENDPOINT"
+	case "api_token":
+		return prefix + "_TOKEN"
+	}
+	return prefix + "_" + envVarName(attribute)
+}
+
+// parameterBindings validates configured parameters. The section describes where they are
+// configured, for error messages.
+func parameterBindings(section string, parameters []ParameterConfig) ([]restutils.ParameterBinding, error) {
+	result := make([]restutils.ParameterBinding, 0, len(parameters))
+	for _, parameter := range parameters {
+		in := restutils.In(strings.ToLower(parameter.In))
+		if in != restutils.InQuery && in != restutils.InHeader && in != restutils.InCookie {
+			return nil, fmt.Errorf("%s, parameter %s must be in query, header, or cookie", section, parameter.Name)
+		}
+		result = append(result, restutils.ParameterBinding{
+			In:   in,
+			Name: parameter.Name,
+		})
+	}
+	return result, nil
+}
+
 func (c *Config) AsBindings() ([]restutils.RESTBinding, error) {
 	result := make([]restutils.RESTBinding, 0, len(c.Output))
 	for key, resource := range c.Output {
@@ -180,11 +781,90 @@ func (c *Config) AsBindings() ([]restutils.RESTBinding, error) {
 				}
 			}
 		}
+		binding.Identity = resource.Identity
+		if binding.Envelope, err = c.Api.ResourceEnvelope(resource); err != nil {
+			return nil, fmt.Errorf("resource %s: %w", key, err)
+		}
+		if binding.Parameters, err = parameterBindings("resource "+key, resource.Parameters); err != nil {
+			return nil, err
+		}
 		result = append(result, binding)
 	}
 	return result, nil
 }
 
+// ParameterBindings returns the parameters common to most operations
+func (a *ApiConfig) ParameterBindings() ([]restutils.ParameterBinding, error) {
+	return parameterBindings("api", a.Parameters)
+}
+
+// Security returns the configured security schemes, keyed by name. A single Scheme is keyed by
+// its type.
+func (a *ApiConfig) Security() (map[string]*SecuritySchemeConfig, error) {
+	schemes := a.SecuritySchemes
+	if len(schemes) == 0 && len(a.Scheme) > 0 {
+		schemes = map[string]*SecuritySchemeConfig{
+			string(a.Scheme): {Type: a.Scheme},
+		}
+	}
+
+	for name, scheme := range schemes {
+		if scheme == nil {
+			return nil, fmt.Errorf("security scheme %s is empty", name)
+		}
+
+		switch scheme.Type {
+		case TokenSecurityScheme, BasicSecurityScheme:
+		case ApiKeySecurityScheme:
+			in := restutils.In(strings.ToLower(scheme.In))
+			if len(scheme.Name) == 0 || (in != restutils.InHeader && in != restutils.InQuery && in != restutils.InCookie) {
+				return nil, fmt.Errorf("security scheme %s must have a name and be in header, query, or cookie", name)
+			}
+		case ClientCredentialsSecurityScheme:
+			if len(scheme.TokenURL) == 0 {
+				return nil, fmt.Errorf("security scheme %s is missing a token_url", name)
+			}
+		default:
+			return nil, fmt.Errorf("security scheme %s has unsupported type \"%s\"", name, scheme.Type)
+		}
+	}
+
+	return schemes, nil
+}
+
+// NewProbe creates a probe for the specified document that is configured with the probe rules
+func (p *ProbeConfig) NewProbe(doc *openapi3.T) (restutils.RESTProbe, error) {
+	probe := restutils.NewProbe(doc)
+	probe.Prefix = p.Prefix
+
+	for index, rule := range p.Rules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return probe, fmt.Errorf("probe rule %d has an invalid pattern: %w", index, err)
+		}
+
+		if len(rule.Resource) == 0 {
+			return probe, fmt.Errorf("probe rule %d is missing a resource name", index)
+		}
+
+		var action restutils.RESTPseudonym
+		if len(rule.Action) > 0 {
+			if action, err = restutils.RESTPseudonymFromString(rule.Action); err != nil {
+				return probe, fmt.Errorf("probe rule %d: %w", index, err)
+			}
+		}
+
+		probe.Rules = append(probe.Rules, restutils.ProbeRule{
+			Pattern: pattern,
+			Name:    rule.Resource,
+			Action:  action,
+			Method:  rule.Method,
+		})
+	}
+
+	return probe, nil
+}
+
 func ReadConfig(path string) (*Config, error) {
 	raw, err := os.ReadFile(path)
 	if err != nil {
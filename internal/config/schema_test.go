@@ -0,0 +1,272 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brandonc/tfpgen/pkg/restutils"
+)
+
+func Test_EnvVar(t *testing.T) {
+	custom := "EXAMPLE_TENANT"
+	provider := ProviderConfig{
+		Name: "brandonc/tfpgen-example",
+		Env: map[string]string{
+			"region":    "NOMAD_REGION",
+			"namespace": "",
+		},
+		Attributes: []ProviderAttribute{
+			{Name: "tenant", Env: &custom},
+			{Name: "insecure"},
+		},
+	}
+
+	cases := map[string]string{
+		"endpoint":  "TFPGEN_EXAMPLE_ENDPOINT",
+		"api_token": "TFPGEN_EXAMPLE_TOKEN",
+		"api_key":   "TFPGEN_EXAMPLE_API_KEY",
+		"region":    "NOMAD_REGION",
+		"namespace": "",
+		"tenant":    "EXAMPLE_TENANT",
+		"insecure":  "TFPGEN_EXAMPLE_INSECURE",
+	}
+
+	for attribute, expected := range cases {
+		if actual := provider.EnvVar(attribute); actual != expected {
+			t.Errorf("expected %s to be read from %q but got %q", attribute, expected, actual)
+		}
+	}
+}
+
+func Test_ProviderAttributeValidate(t *testing.T) {
+	empty := ""
+
+	valid := []ProviderAttribute{
+		{Name: "region"},
+		{Name: "region", Default: "us-east-1", Env: &empty},
+		{Name: "insecure", Type: ProviderAttributeBool, Default: false, Option: InsecureSkipVerifyOption},
+		{Name: "timeout", Default: "30s", Option: TimeoutOption},
+		{Name: "retries", Type: ProviderAttributeInt, Default: 5, Option: MaxRetriesOption},
+		{Name: "page_size", Type: ProviderAttributeInt, Default: 10, In: "query", Parameter: "per_page"},
+		{Name: "ratio", Type: ProviderAttributeFloat, Default: 1},
+		{Name: "tags", Type: ProviderAttributeList, Default: []interface{}{"a", "b"}},
+		{Name: "tenant", In: "Header", Parameter: "X-Tenant"},
+	}
+
+	for _, att := range valid {
+		if err := att.Validate(); err != nil {
+			t.Errorf("expected %s to be valid, got %s", att.Name, err)
+		}
+	}
+
+	invalid := []ProviderAttribute{
+		{Name: "my region"},
+		{Name: "region", Type: "duration"},
+		{Name: "insecure", Type: ProviderAttributeBool, Default: "yes"},
+		{Name: "tags", Type: ProviderAttributeList, Default: []interface{}{1}},
+		{Name: "tenant", In: "path", Parameter: "tenant"},
+		{Name: "tenant", In: "header"},
+		{Name: "tags", Type: ProviderAttributeList, In: "query", Parameter: "tags"},
+		{Name: "insecure", Option: InsecureSkipVerifyOption},
+		{Name: "retries", Type: ProviderAttributeInt, Option: "retries"},
+		{Name: "retries", Option: MaxRetriesOption},
+	}
+
+	for _, att := range invalid {
+		if err := att.Validate(); err == nil {
+			t.Errorf("expected %s to be invalid: %#v", att.Name, att)
+		}
+	}
+}
+
+func Test_Variables(t *testing.T) {
+	api := ApiConfig{
+		DefaultEndpoint: "{scheme}://{region}.api.example.com/{region}",
+		ServerVariables: map[string]*ServerVariableConfig{
+			"region": {Default: "us", Enum: []string{"us", "eu"}},
+			"scheme": {Default: "https"},
+		},
+	}
+
+	names, err := api.Variables()
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if len(names) != 2 || names[0] != "scheme" || names[1] != "region" {
+		t.Errorf("expected the variables in endpoint order, got %v", names)
+	}
+
+	api.ServerVariables["region"].Default = "ap"
+	if _, err := api.Variables(); err == nil {
+		t.Error("expected a default outside the enum to be invalid")
+	}
+
+	api.ServerVariables["region"].Default = "us"
+	api.ServerVariables["tenant"] = &ServerVariableConfig{}
+	if _, err := api.Variables(); err == nil {
+		t.Error("expected a variable missing from the endpoint to be invalid")
+	}
+
+	delete(api.ServerVariables, "scheme")
+	delete(api.ServerVariables, "tenant")
+	if _, err := api.Variables(); err == nil {
+		t.Error("expected an undefined endpoint variable to be invalid")
+	}
+}
+
+func Test_AsyncConfigValidate(t *testing.T) {
+	valid := []AsyncConfig{
+		{},
+		{Header: "Operation-Location", StatusPointer: "/status", Success: []string{"succeeded"}, Failure: []string{"failed"}},
+		{URLPointer: "/links/status", Interval: "500ms"},
+	}
+
+	for _, async := range valid {
+		if err := async.Validate(); err != nil {
+			t.Errorf("expected %#v to be valid, got %s", async, err)
+		}
+	}
+
+	invalid := []AsyncConfig{
+		{URLPointer: "links/status"},
+		{StatusPointer: "/status"},
+		{Success: []string{"succeeded"}},
+		{Interval: "often"},
+	}
+
+	for _, async := range invalid {
+		if err := async.Validate(); err == nil {
+			t.Errorf("expected %#v to be invalid", async)
+		}
+	}
+}
+
+func Test_NotFoundConfigValidate(t *testing.T) {
+	valid := NotFoundConfig{Read: []int{404, 410}, Delete: []int{404}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected %#v to be valid, got %s", valid, err)
+	}
+
+	invalid := []NotFoundConfig{
+		{Read: []int{500}},
+		{Delete: []int{204}},
+	}
+
+	for _, notFound := range invalid {
+		if err := notFound.Validate(); err == nil {
+			t.Errorf("expected %#v to be invalid", notFound)
+		}
+	}
+}
+
+func Test_ConsistencyDurations(t *testing.T) {
+	interval, timeout, err := (&ConsistencyConfig{}).Durations()
+	if err != nil || interval != 2*time.Second || timeout != 2*time.Minute {
+		t.Errorf("expected the default durations, got %s %s %v", interval, timeout, err)
+	}
+
+	interval, timeout, err = (&ConsistencyConfig{Interval: "500ms", Timeout: "30s"}).Durations()
+	if err != nil || interval != 500*time.Millisecond || timeout != 30*time.Second {
+		t.Errorf("expected the configured durations, got %s %s %v", interval, timeout, err)
+	}
+
+	invalid := []ConsistencyConfig{
+		{Interval: "often"},
+		{Timeout: "-1m"},
+		{Interval: "5m", Timeout: "1m"},
+	}
+
+	for _, consistency := range invalid {
+		if _, _, err := consistency.Durations(); err == nil {
+			t.Errorf("expected %#v to be invalid", consistency)
+		}
+	}
+}
+
+func Test_TimeoutsDefault(t *testing.T) {
+	timeouts := TimeoutsConfig{Create: "1h", Delete: "90s"}
+
+	cases := map[restutils.RESTPseudonym]time.Duration{
+		restutils.Create: time.Hour,
+		restutils.Show:   20 * time.Minute,
+		restutils.Update: 20 * time.Minute,
+		restutils.Delete: 90 * time.Second,
+	}
+
+	for action, expected := range cases {
+		actual, err := timeouts.Default(action)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if actual != expected {
+			t.Errorf("expected the %s timeout to be %s, got %s", action, expected, actual)
+		}
+	}
+
+	for _, invalid := range []TimeoutsConfig{{Read: "soon"}, {Read: "-1m"}} {
+		if _, err := invalid.Default(restutils.Show); err == nil {
+			t.Errorf("expected %#v to be invalid", invalid)
+		}
+	}
+}
+
+func Test_ResourceEnvelope(t *testing.T) {
+	api := ApiConfig{Envelope: "/data"}
+
+	cases := map[string]TerraformResource{
+		"/data":          {},
+		"/result/record": {Envelope: "/result/record"},
+	}
+
+	for expected, resource := range cases {
+		actual, err := api.ResourceEnvelope(&resource)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if actual != expected {
+			t.Errorf("expected the envelope to be %q, got %q", expected, actual)
+		}
+	}
+
+	if _, err := api.ResourceEnvelope(&TerraformResource{Envelope: "data"}); err == nil {
+		t.Error("expected an envelope that is not a JSON pointer to be invalid")
+	}
+}
+
+func Test_DefaultRetryStatusCodes(t *testing.T) {
+	api := ApiConfig{RetryStatusCodes: []int{429, 503}}
+	if codes, err := api.DefaultRetryStatusCodes(); err != nil || len(codes) != 2 {
+		t.Errorf("expected the configured status codes, got %v and error %v", codes, err)
+	}
+
+	if codes, err := (&ApiConfig{}).DefaultRetryStatusCodes(); err != nil || len(codes) > 0 {
+		t.Errorf("expected no status codes by default, got %v and error %v", codes, err)
+	}
+
+	if _, err := (&ApiConfig{RetryStatusCodes: []int{302}}).DefaultRetryStatusCodes(); err == nil {
+		t.Error("expected a redirect status code to be invalid")
+	}
+}
+
+func Test_AttributeMappings(t *testing.T) {
+	resource := TerraformResource{Attributes: []AttributeConfig{{Name: "labels", Pointer: "/spec/labels"}}}
+	mappings, err := resource.AttributeMappings()
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if len(mappings) != 1 || mappings[0] != (restutils.AttributeMapping{Name: "labels", Pointer: "/spec/labels"}) {
+		t.Errorf("expected the labels mapping, got %v", mappings)
+	}
+
+	invalid := [][]AttributeConfig{
+		{{Name: "0labels", Pointer: "/spec/labels"}},
+		{{Name: "labels", Pointer: "spec/labels"}},
+		{{Name: "labels", Pointer: "/spec/labels"}, {Name: "labels", Pointer: "/labels"}},
+	}
+	for _, attributes := range invalid {
+		resource := TerraformResource{Attributes: attributes}
+		if _, err := resource.AttributeMappings(); err == nil {
+			t.Errorf("expected %v to be invalid", attributes)
+		}
+	}
+}
@@ -0,0 +1,1738 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/brandonc/tfpgen/internal/config"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ClientGenerator is the type that generates the HTTP API client shared by all resources
+type ClientGenerator struct {
+	Config *config.Config
+	Doc    *openapi3.T
+}
+
+type ClientGeneratorData struct {
+	PackageName string
+}
+
+var _ Generator = (*ClientGenerator)(nil)
+
+func (g *ClientGenerator) Template() string {
+	return `// Code generated by tfpgen; DO NOT EDIT.
+package {{ .PackageName }}
+
+import (
+	"bytes"
+	"context"
+	cryptorand "crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client is the HTTP API client shared by all resources
+type Client struct {
+	Endpoint   string
+	HTTPClient *http.Client
+
+	// Auth are the configured credentials, keyed by security scheme name
+	Auth map[string]Authenticator
+
+	// Parameters are the values of parameters configured for the provider, which are sent
+	// with each operation that declares them
+	Parameters map[Parameter]string
+
+	// GlobalParameters are the values of custom provider attributes that are sent with every
+	// request
+	GlobalParameters map[Parameter]interface{}
+
+	// Settings are the values of configured custom provider attributes, keyed by attribute name
+	Settings map[string]interface{}
+
+	// MaxRetries is the number of times a request is retried after a transient failure.
+	// Retries wait between RetryWaitMin and RetryWaitMax, doubling with each attempt, unless
+	// the response specifies how long to wait with a Retry-After header. Retry-After waits are
+	// also limited to RetryWaitMax.
+	MaxRetries   int
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+}
+
+// Default retry settings of new clients
+const (
+	DefaultMaxRetries   = 3
+	DefaultRetryWaitMin = time.Second
+	DefaultRetryWaitMax = 30 * time.Second
+)
+
+// Parameter identifies a query, header, or cookie parameter
+type Parameter struct {
+	In   string
+	Name string
+}
+
+// Operation describes the API operation bound to a resource action
+type Operation struct {
+	Method    string
+	Path      string
+	MediaType string
+
+	// Parameters are the provider parameters the operation declares
+	Parameters []Parameter
+
+	// Security lists the alternative security requirements of the operation. If nil, all
+	// configured credentials are sent. If empty, the operation requires no credentials.
+	Security []SecurityRequirement
+
+	// Retry lists the response status codes that are retried. Operations that are not
+	// idempotent are only retried when the request was rate limited, since the API did
+	// not process it.
+	Retry []int
+
+	// Async describes how to wait for the operation to complete if it responds with 202
+	// Accepted. If nil, the operation is complete when the API responds.
+	Async *Async
+
+	// NotFound lists the response status codes that indicate the resource does not exist
+	NotFound []int
+
+	// Errors locates the parts of documented error response bodies. If nil, errors are
+	// described by the whole response body.
+	Errors *ErrorSchema
+
+	// Patch is the format of partial update request bodies: "merge" sends the changed
+	// attributes as a JSON Merge Patch, and "json" sends a JSON Patch. If empty, the request
+	// body replaces the resource.
+	Patch string
+
+	// RequestMediaType is the media type of request bodies, if it is not MediaType. Form
+	// encoded and multipart bodies are sent as form fields.
+	RequestMediaType string
+
+	// Files lists the properties of multipart request bodies that hold the paths of files,
+	// whose contents are uploaded
+	Files []string
+
+	// IdempotencyKey is the name of the header that identifies each call of the operation,
+	// so that the API processes it once however often it is retried. If empty, no key is sent.
+	IdempotencyKey string
+
+	// Envelope is a JSON pointer to the resource within request and response bodies, like
+	// "/data". If empty, the bodies are the resource.
+	Envelope string
+
+	// JSONAPI describes the JSON:API resource object within the envelope. If nil, the
+	// resource is not a JSON:API resource object.
+	JSONAPI *JSONAPI
+
+	// Pointers maps properties of the resource body to JSON pointers within the resource, like
+	// "/spec/template/metadata/labels", for attributes that flatten nested properties
+	Pointers map[string]string
+}
+
+// JSONAPI describes a JSON:API resource object, whose attributes are sent as "attributes" and
+// whose relationship attributes are sent as resource identifiers in "relationships"
+type JSONAPI struct {
+	Type          string
+	Relationships []Relationship
+}
+
+// Relationship describes a JSON:API relationship whose related IDs are held by the Attribute
+type Relationship struct {
+	Name      string
+	Attribute string
+	Type      string
+	Many      bool
+}
+
+// ErrorSchema locates the parts of an error response body, as JSON pointers. Details locates an
+// array of field errors, and DetailField and DetailMessage locate the parts of each one.
+type ErrorSchema struct {
+	Code          string
+	Message       string
+	Details       string
+	DetailField   string
+	DetailMessage string
+}
+
+// Async describes how to poll the status of an operation that was accepted. The status URL
+// is taken from the Header of the response, or from the response body at URLPointer. Without
+// either, a delete operation polls the deleted resource until it is gone.
+type Async struct {
+	Header     string
+	URLPointer string
+
+	// StatusPointer locates the status value in the status response body. The operation is
+	// complete when the value is one of Success, and has failed when it is one of Failure. If
+	// empty, the operation is complete when the status response is not 202 Accepted.
+	StatusPointer string
+	Success       []string
+	Failure       []string
+
+	// Interval is the time between polls, unless the status response has a Retry-After header
+	Interval time.Duration
+}
+
+// Idempotent reports whether the operation can be repeated without changing its result
+func (op *Operation) Idempotent() bool {
+	if op.IdempotencyKey != "" {
+		return true
+	}
+
+	switch op.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryable reports whether a response status code or transport error should be retried
+func (op *Operation) retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		// Only transport errors are retried, except those that will not go away, like an
+		// untrusted certificate. They may happen after the request was processed.
+		if _, ok := err.(*url.Error); !ok || ctx.Err() != nil || !op.Idempotent() {
+			return false
+		}
+
+		var unknownAuthority x509.UnknownAuthorityError
+		var hostname x509.HostnameError
+		var invalid x509.CertificateInvalidError
+		return !errors.As(err, &unknownAuthority) && !errors.As(err, &hostname) && !errors.As(err, &invalid)
+	}
+
+	for _, code := range op.Retry {
+		if resp.StatusCode == code {
+			return op.Idempotent() || code == http.StatusTooManyRequests
+		}
+	}
+	return false
+}
+
+// SecurityRequirement maps the names of the security schemes required together by an operation
+// to the scopes each must grant
+type SecurityRequirement map[string][]string
+
+// SecurityError is returned when the configured credentials cannot satisfy any of the security
+// requirements of an operation
+type SecurityError struct {
+	Method       string
+	Path         string
+	Requirements []SecurityRequirement
+}
+
+func (e *SecurityError) Error() string {
+	alternatives := make([]string, 0, len(e.Requirements))
+	for _, requirement := range e.Requirements {
+		schemes := make([]string, 0, len(requirement))
+		for _, name := range sortedKeys(requirement) {
+			if scopes := requirement[name]; len(scopes) > 0 {
+				name = fmt.Sprintf("%s (scopes %s)", name, strings.Join(scopes, ", "))
+			}
+			schemes = append(schemes, name)
+		}
+		alternatives = append(alternatives, strings.Join(schemes, " and "))
+	}
+
+	return fmt.Sprintf(
+		"the configured credentials cannot satisfy the security requirements of %s %s, which requires %s",
+		e.Method, e.Path, strings.Join(alternatives, ", or "),
+	)
+}
+
+// ErrorSummary summarizes an error returned by the client, for use in diagnostics
+func ErrorSummary(err error) string {
+	var securityErr *SecurityError
+	if errors.As(err, &securityErr) {
+		return "Missing Credentials"
+	}
+	return "Client Error"
+}
+
+// IsNotFound reports whether an error returned by the client is one of the not found status
+// codes of the operation
+func IsNotFound(op *Operation, err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	for _, code := range op.NotFound {
+		if apiErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusCode returns the response status code of an error returned by the client, or 0 if the
+// API did not respond
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// IsPreconditionFailed reports whether an error returned by the client is 412 Precondition
+// Failed, which means the resource was changed since its entity tag was read
+func IsPreconditionFailed(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusPreconditionFailed
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Request describes the parameters and body of a single operation call
+type Request struct {
+	PathParams map[string]string
+	Query      url.Values
+	Header     http.Header
+	Cookies    []*http.Cookie
+	Body       interface{}
+
+	// Prior is the prior state of the Body. Partial updates send the difference between them.
+	Prior interface{}
+}
+
+// Response is the result of a successful operation call
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// Accepted reports whether the operation completed asynchronously, in which case the
+	// response is the one that accepted the operation, and the resource should be read again
+	Accepted bool
+}
+
+// AsyncError is returned when an asynchronous operation reports that it failed
+type AsyncError struct {
+	Method string
+	Path   string
+	Status string
+	Body   []byte
+}
+
+func (e *AsyncError) Error() string {
+	return fmt.Sprintf("%s %s was accepted, but failed with status %q: %s", e.Method, e.Path, e.Status, e.Body)
+}
+
+// APIError is returned when the API responds with an unsuccessful status code
+type APIError struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+
+	// Code and Message are decoded from a documented error response body
+	Code    string
+	Message string
+
+	// Fields are the field errors decoded from a documented error response body
+	Fields []FieldError
+}
+
+// FieldError describes a request field that was rejected by the API
+type FieldError struct {
+	// Path is the API name of the field and of each property or list index it is nested in
+	Path    []string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if len(e.Message) > 0 {
+		var b strings.Builder
+		b.WriteString(e.Status + ": " + e.Message)
+		if len(e.Code) > 0 {
+			b.WriteString(" (" + e.Code + ")")
+		}
+		for _, field := range e.Fields {
+			fmt.Fprintf(&b, "\n  %s: %s", strings.Join(field.Path, "."), field.Message)
+		}
+		return b.String()
+	}
+
+	if len(e.Body) > 0 {
+		return fmt.Sprintf("%s: %s", e.Status, e.Body)
+	}
+	return e.Status
+}
+
+// decode sets the code, message, and field errors of the error from its response body. The
+// error is unchanged if the body does not match the schema.
+func (e *APIError) decode(schema *ErrorSchema) {
+	var document interface{}
+	if err := json.Unmarshal(e.Body, &document); err != nil {
+		return
+	}
+
+	message, ok := jsonPointer(document, schema.Message)
+	if !ok || message == nil {
+		return
+	}
+	e.Message = fmt.Sprint(message)
+
+	if schema.Code != "" {
+		if code, ok := jsonPointer(document, schema.Code); ok && code != nil {
+			e.Code = fmt.Sprint(code)
+		}
+	}
+
+	if schema.Details == "" {
+		return
+	}
+
+	details, _ := jsonPointer(document, schema.Details)
+	items, _ := details.([]interface{})
+	for _, item := range items {
+		field, ok := jsonPointer(item, schema.DetailField)
+		if !ok {
+			continue
+		}
+		message, ok := jsonPointer(item, schema.DetailMessage)
+		if !ok {
+			continue
+		}
+
+		path := fieldPath(fmt.Sprint(field))
+		if len(path) > 0 {
+			e.Fields = append(e.Fields, FieldError{Path: path, Message: fmt.Sprint(message)})
+		}
+	}
+}
+
+// fieldPath splits the name of a rejected field into the names and list indexes it is nested
+// in. The name is a JSON pointer, like "/limits/0/cpu", or a dotted path, like "limits[0].cpu"
+// or "$.limits[0].cpu".
+func fieldPath(field string) []string {
+	if strings.HasPrefix(field, "/") {
+		result := make([]string, 0)
+		for _, token := range strings.Split(field[1:], "/") {
+			result = append(result, strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~"))
+		}
+		return result
+	}
+
+	field = strings.TrimPrefix(strings.TrimPrefix(field, "$"), ".")
+	return strings.FieldsFunc(field, func(r rune) bool {
+		return r == '.' || r == '[' || r == ']'
+	})
+}
+
+// Authenticator applies the credentials of a security scheme to a request
+type Authenticator interface {
+	Authenticate(ctx context.Context, c *Client, req *http.Request) error
+}
+
+// BearerAuth sends a token as an Authorization: Bearer header
+type BearerAuth struct {
+	Token string
+}
+
+func (a *BearerAuth) Authenticate(_ context.Context, _ *Client, req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	return nil
+}
+
+// APIKeyAuth sends a key as a header, query, or cookie parameter
+type APIKeyAuth struct {
+	In    string
+	Name  string
+	Value string
+}
+
+func (a *APIKeyAuth) Authenticate(_ context.Context, _ *Client, req *http.Request) error {
+	switch a.In {
+	case "header":
+		req.Header.Set(a.Name, a.Value)
+	case "query":
+		query := req.URL.Query()
+		query.Set(a.Name, a.Value)
+		req.URL.RawQuery = query.Encode()
+	case "cookie":
+		req.AddCookie(&http.Cookie{Name: a.Name, Value: a.Value})
+	default:
+		return fmt.Errorf("cannot send API key %s in %s", a.Name, a.In)
+	}
+	return nil
+}
+
+// BasicAuth sends a username and password using HTTP basic authentication
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+func (a *BasicAuth) Authenticate(_ context.Context, _ *Client, req *http.Request) error {
+	req.SetBasicAuth(a.Username, a.Password)
+	return nil
+}
+
+// ClientCredentialsAuth exchanges a client ID and secret for an OAuth2 access token, which is
+// sent as an Authorization: Bearer header. The token is cached until shortly before it expires.
+type ClientCredentialsAuth struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// tokenExpiryLeeway is how long before its expiry a cached access token is refreshed
+const tokenExpiryLeeway = 30 * time.Second
+
+type tokenResponse struct {
+	AccessToken string ` + "`json:\"access_token\"`" + `
+	TokenType   string ` + "`json:\"token_type\"`" + `
+	ExpiresIn   int    ` + "`json:\"expires_in\"`" + `
+}
+
+// ScopedAuthenticator is implemented by authenticators that grant a limited set of scopes
+type ScopedAuthenticator interface {
+	HasScopes(scopes []string) bool
+}
+
+// Invalidator is implemented by authenticators whose credentials can be discarded and fetched
+// again after the API rejects them
+type Invalidator interface {
+	Invalidate()
+}
+
+func (a *ClientCredentialsAuth) Authenticate(ctx context.Context, c *Client, req *http.Request) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == "" || (!a.expiry.IsZero() && time.Now().Add(tokenExpiryLeeway).After(a.expiry)) {
+		token, err := a.fetchToken(ctx, c.HTTPClient)
+		if err != nil {
+			return err
+		}
+
+		a.token = token.AccessToken
+		a.expiry = time.Time{}
+		if token.ExpiresIn > 0 {
+			a.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+		}
+	}
+
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+// HasScopes reports whether the requested scopes include all the specified scopes. If no
+// scopes are requested, the token endpoint grants its default scopes, which are assumed to
+// be sufficient.
+func (a *ClientCredentialsAuth) HasScopes(scopes []string) bool {
+	if len(a.Scopes) == 0 {
+		return true
+	}
+
+	for _, scope := range scopes {
+		found := false
+		for _, requested := range a.Scopes {
+			found = found || requested == scope
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Invalidate discards the cached access token, so that a new one is fetched
+func (a *ClientCredentialsAuth) Invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.token = ""
+	a.expiry = time.Time{}
+}
+
+func (a *ClientCredentialsAuth) fetchToken(ctx context.Context, httpClient *http.Client) (*tokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", a.ClientID)
+	form.Set("client_secret", a.ClientSecret)
+	if len(a.Scopes) > 0 {
+		form.Set("scope", strings.Join(a.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read access token: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("could not fetch access token: %w", &APIError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       body,
+		})
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("could not decode access token: %w", err)
+	}
+
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("the token response did not include an access token")
+	}
+	return &token, nil
+}
+
+// NewClient creates a new API client
+func NewClient(endpoint string) *Client {
+	return &Client{
+		Endpoint:   endpoint,
+		HTTPClient: http.DefaultClient,
+		Auth:       make(map[string]Authenticator),
+		Parameters: make(map[Parameter]string),
+
+		GlobalParameters: make(map[Parameter]interface{}),
+		Settings:         make(map[string]interface{}),
+
+		MaxRetries:   DefaultMaxRetries,
+		RetryWaitMin: DefaultRetryWaitMin,
+		RetryWaitMax: DefaultRetryWaitMax,
+	}
+}
+
+// SetInsecureSkipVerify configures whether the client verifies the API server certificate
+func (c *Client) SetInsecureSkipVerify(insecure bool) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: insecure}
+
+	httpClient := *c.HTTPClient
+	httpClient.Transport = transport
+	c.HTTPClient = &httpClient
+}
+
+// SetTimeout limits the duration of each request, like "30s". A zero duration means no timeout.
+func (c *Client) SetTimeout(timeout string) error {
+	duration, err := time.ParseDuration(timeout)
+	if err != nil {
+		return err
+	}
+
+	httpClient := *c.HTTPClient
+	httpClient.Timeout = duration
+	c.HTTPClient = &httpClient
+	return nil
+}
+
+// NewRequest creates an empty operation request
+func NewRequest() *Request {
+	return &Request{
+		PathParams: make(map[string]string),
+		Query:      make(url.Values),
+		Header:     make(http.Header),
+	}
+}
+
+// Set sets a path, query, header, or cookie parameter. Nil values are not sent.
+func (r *Request) Set(in, name string, value interface{}) {
+	s, ok := paramValue(value)
+	if !ok {
+		return
+	}
+
+	switch in {
+	case "path":
+		r.PathParams[name] = s
+	case "query":
+		r.Query.Set(name, s)
+	case "header":
+		r.Header.Set(name, s)
+	case "cookie":
+		r.Cookies = append(r.Cookies, &http.Cookie{Name: name, Value: s})
+	}
+}
+
+// Has reports whether a parameter has been set
+func (r *Request) Has(in, name string) bool {
+	switch in {
+	case "path":
+		_, ok := r.PathParams[name]
+		return ok
+	case "query":
+		_, ok := r.Query[name]
+		return ok
+	case "header":
+		_, ok := r.Header[http.CanonicalHeaderKey(name)]
+		return ok
+	case "cookie":
+		for _, cookie := range r.Cookies {
+			if cookie.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// paramValue formats a parameter value, dereferencing pointers. The result is false
+// if the value is nil.
+func paramValue(value interface{}) (string, bool) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return "", false
+	}
+	return fmt.Sprint(v.Interface()), true
+}
+
+// stringPtr formats a value as a string pointer, or nil if the value is nil
+func stringPtr(value interface{}) *string {
+	s, ok := paramValue(value)
+	if !ok {
+		return nil
+	}
+	return &s
+}
+
+func (c *Client) url(op *Operation, req *Request) (string, error) {
+	path := op.Path
+	for name, value := range req.PathParams {
+		path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(value))
+	}
+
+	if strings.Contains(path, "{") {
+		return "", fmt.Errorf("missing path parameters for %s", path)
+	}
+
+	result := strings.TrimSuffix(c.Endpoint, "/") + path
+	if len(req.Query) > 0 {
+		result += "?" + req.Query.Encode()
+	}
+	return result, nil
+}
+
+// authenticators selects the credentials that satisfy the first satisfiable security
+// requirement of the operation. A requirement of no credentials is only used if no other
+// requirement can be satisfied.
+func (c *Client) authenticators(op *Operation) ([]Authenticator, error) {
+	if op.Security == nil {
+		result := make([]Authenticator, 0, len(c.Auth))
+		for _, name := range sortedKeys(c.Auth) {
+			result = append(result, c.Auth[name])
+		}
+		return result, nil
+	}
+
+	anonymous := len(op.Security) == 0
+	for _, requirement := range op.Security {
+		if len(requirement) == 0 {
+			anonymous = true
+			continue
+		}
+
+		if result, ok := c.satisfy(requirement); ok {
+			return result, nil
+		}
+	}
+
+	if anonymous {
+		return nil, nil
+	}
+
+	return nil, &SecurityError{
+		Method:       op.Method,
+		Path:         op.Path,
+		Requirements: op.Security,
+	}
+}
+
+// satisfy returns the credentials of each security scheme in the requirement, if they are
+// configured and grant the required scopes
+func (c *Client) satisfy(requirement SecurityRequirement) ([]Authenticator, bool) {
+	result := make([]Authenticator, 0, len(requirement))
+	for _, name := range sortedKeys(requirement) {
+		auth, ok := c.Auth[name]
+		if !ok {
+			return nil, false
+		}
+
+		if scoped, ok := auth.(ScopedAuthenticator); ok && !scoped.HasScopes(requirement[name]) {
+			return nil, false
+		}
+		result = append(result, auth)
+	}
+	return result, true
+}
+
+// invalidate discards credentials that can be fetched again. The result is false if there are
+// none.
+func invalidate(auths []Authenticator) bool {
+	result := false
+	for _, auth := range auths {
+		if invalidator, ok := auth.(Invalidator); ok {
+			invalidator.Invalidate()
+			result = true
+		}
+	}
+	return result
+}
+
+// send sends a single authenticated request and reads the response body
+func (c *Client) send(ctx context.Context, op *Operation, auths []Authenticator, u string, req *Request, body []byte) (*http.Response, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, op.Method, u, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for name, values := range req.Header {
+		httpReq.Header[name] = values
+	}
+	for _, cookie := range req.Cookies {
+		httpReq.AddCookie(cookie)
+	}
+	httpReq.Header.Set("Accept", op.MediaType)
+
+	for _, auth := range auths {
+		if err := auth.Authenticate(ctx, c, httpReq); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	httpResp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read response body: %w", err)
+	}
+	return httpResp, respBody, nil
+}
+
+// jitter randomizes retry waits, so that clients rate limited together do not retry together
+var (
+	jitter   = rand.New(rand.NewSource(time.Now().UnixNano()))
+	jitterMu sync.Mutex
+)
+
+// retryAfter parses the Retry-After header of a response, in seconds or as a date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// backoff returns how long to wait before retrying a request. A Retry-After header, in
+// seconds or as a date, is respected up to RetryWaitMax. Otherwise, the wait doubles with each
+// attempt up to RetryWaitMax, and a random half of it is added to the other half.
+func (c *Client) backoff(attempt int, resp *http.Response) time.Duration {
+	if wait, ok := retryAfter(resp); ok {
+		if wait > c.RetryWaitMax {
+			return c.RetryWaitMax
+		}
+		return wait
+	}
+
+	wait := c.RetryWaitMin
+	for i := 0; i < attempt && wait < c.RetryWaitMax; i++ {
+		wait *= 2
+	}
+	if wait > c.RetryWaitMax {
+		wait = c.RetryWaitMax
+	}
+	if wait <= 0 {
+		return 0
+	}
+
+	jitterMu.Lock()
+	defer jitterMu.Unlock()
+	return wait/2 + time.Duration(jitter.Int63n(int64(wait/2)+1))
+}
+
+// sendWithRetries sends the request, retrying transient failures of the operation until
+// MaxRetries is reached or the context is done. The failure is returned without waiting if
+// the context would be done before the request is retried.
+func (c *Client) sendWithRetries(ctx context.Context, op *Operation, auths []Authenticator, u string, req *Request, body []byte) (*http.Response, []byte, error) {
+	for attempt := 0; ; attempt++ {
+		httpResp, respBody, err := c.send(ctx, op, auths, u, req, body)
+		if attempt >= c.MaxRetries || !op.retryable(ctx, httpResp, err) {
+			return httpResp, respBody, err
+		}
+
+		wait := c.backoff(attempt, httpResp)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return httpResp, respBody, err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if err == nil {
+				err = ctx.Err()
+			}
+			return nil, nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// newIdempotencyKey returns a random (version 4) UUID
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := cryptorand.Read(b); err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// matchesPlan reports whether each of the properties of the current body equals that of the
+// planned body. Properties that are not planned, like those computed by the API, are ignored.
+func matchesPlan(planned, current interface{}, properties []string) (bool, error) {
+	currentDocument, err := toDocument(current)
+	if err != nil {
+		return false, err
+	}
+
+	plannedObject, _ := planned.(map[string]interface{})
+	currentObject, _ := currentDocument.(map[string]interface{})
+	for _, property := range properties {
+		value, ok := plannedObject[property]
+		if ok && value != nil && !reflect.DeepEqual(value, currentObject[property]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// toDocument converts a value to its decoded JSON document
+func toDocument(value interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var result interface{}
+	err = json.Unmarshal(encoded, &result)
+	return result, err
+}
+
+// envelop wraps a value in the objects of a JSON pointer, so that the pointer locates it
+func envelop(pointer string, value interface{}) interface{} {
+	if pointer == "" {
+		return value
+	}
+
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i := len(tokens) - 1; i >= 0; i-- {
+		token := strings.ReplaceAll(strings.ReplaceAll(tokens[i], "~1", "/"), "~0", "~")
+		value = map[string]interface{}{token: value}
+	}
+	return value
+}
+
+// encode converts a resource body to a JSON:API resource object
+func (j *JSONAPI) encode(body interface{}) (interface{}, error) {
+	document, err := toDocument(body)
+	if err != nil {
+		return nil, err
+	}
+
+	object, ok := document.(map[string]interface{})
+	if !ok {
+		return document, nil
+	}
+
+	attributes := make(map[string]interface{}, len(object))
+	for name, value := range object {
+		attributes[name] = value
+	}
+
+	result := map[string]interface{}{"type": j.Type}
+	if id, ok := attributes["id"]; ok {
+		result["id"] = id
+		delete(attributes, "id")
+	}
+
+	relationships := make(map[string]interface{})
+	for _, relationship := range j.Relationships {
+		value, ok := attributes[relationship.Attribute]
+		if !ok {
+			continue
+		}
+		delete(attributes, relationship.Attribute)
+
+		if ids, ok := value.([]interface{}); ok {
+			data := make([]interface{}, 0, len(ids))
+			for _, id := range ids {
+				data = append(data, map[string]interface{}{"type": relationship.Type, "id": id})
+			}
+			relationships[relationship.Name] = map[string]interface{}{"data": data}
+		} else if value != nil {
+			relationships[relationship.Name] = map[string]interface{}{"data": map[string]interface{}{"type": relationship.Type, "id": value}}
+		} else {
+			relationships[relationship.Name] = map[string]interface{}{"data": nil}
+		}
+	}
+
+	result["attributes"] = attributes
+	if len(relationships) > 0 {
+		result["relationships"] = relationships
+	}
+	return result, nil
+}
+
+// patch returns the members of a resource object that changed since the prior resource object.
+// The type and id are always included, since they identify the resource being updated, and a
+// changed relationship is sent whole, since its resource linkage is replaced.
+func (j *JSONAPI) patch(prior, object map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{"type": j.Type}
+	if id, ok := object["id"]; ok {
+		result["id"] = id
+	} else if id, ok := prior["id"]; ok {
+		result["id"] = id
+	}
+
+	priorAttributes, _ := prior["attributes"].(map[string]interface{})
+	attributes, _ := object["attributes"].(map[string]interface{})
+	if changed := mergePatch(priorAttributes, attributes); len(changed) > 0 {
+		result["attributes"] = changed
+	}
+
+	priorRelationships, _ := prior["relationships"].(map[string]interface{})
+	relationships, _ := object["relationships"].(map[string]interface{})
+	changed := make(map[string]interface{})
+	for name, value := range relationships {
+		if !reflect.DeepEqual(priorRelationships[name], value) {
+			changed[name] = value
+		}
+	}
+	for name := range priorRelationships {
+		if _, ok := relationships[name]; !ok {
+			changed[name] = map[string]interface{}{"data": nil}
+		}
+	}
+	if len(changed) > 0 {
+		result["relationships"] = changed
+	}
+	return result
+}
+
+// decode converts an encoded JSON:API resource object to an encoded resource body
+func (j *JSONAPI) decode(resource []byte) ([]byte, error) {
+	var object map[string]interface{}
+	if err := json.Unmarshal(resource, &object); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{})
+	if attributes, ok := object["attributes"].(map[string]interface{}); ok {
+		for name, value := range attributes {
+			result[name] = value
+		}
+	}
+	if id, ok := object["id"]; ok {
+		result["id"] = id
+	}
+
+	relationships, _ := object["relationships"].(map[string]interface{})
+	for _, relationship := range j.Relationships {
+		value, _ := relationships[relationship.Name].(map[string]interface{})
+		switch data := value["data"].(type) {
+		case []interface{}:
+			ids := make([]interface{}, 0, len(data))
+			for _, identifier := range data {
+				if identifier, ok := identifier.(map[string]interface{}); ok {
+					ids = append(ids, identifier["id"])
+				}
+			}
+			result[relationship.Attribute] = ids
+		case map[string]interface{}:
+			result[relationship.Attribute] = data["id"]
+		}
+	}
+	return json.Marshal(result)
+}
+
+// nest moves the properties of a resource body to the JSON pointers they are mapped to, adding
+// the objects that contain them
+func nest(body interface{}, pointers map[string]string) (interface{}, error) {
+	document, err := toDocument(body)
+	if err != nil {
+		return nil, err
+	}
+
+	object, ok := document.(map[string]interface{})
+	if !ok {
+		return document, nil
+	}
+
+	for _, name := range sortedKeys(pointers) {
+		value, ok := object[name]
+		if !ok {
+			continue
+		}
+		delete(object, name)
+
+		tokens := strings.Split(strings.TrimPrefix(pointers[name], "/"), "/")
+		parent := object
+		for i, token := range tokens {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			if i == len(tokens)-1 {
+				parent[token] = value
+				break
+			}
+
+			nested, ok := parent[token].(map[string]interface{})
+			if !ok {
+				nested = make(map[string]interface{})
+				parent[token] = nested
+			}
+			parent = nested
+		}
+	}
+	return object, nil
+}
+
+// flatten sets the properties of an encoded resource body to the values at the JSON pointers
+// they are mapped to. The values are removed from the objects that contained them, and so are
+// the objects they leave empty, which would otherwise be read as objects that were configured.
+func flatten(resource []byte, pointers map[string]string) ([]byte, error) {
+	var document interface{}
+	if err := json.Unmarshal(resource, &document); err != nil {
+		return nil, err
+	}
+
+	object, ok := document.(map[string]interface{})
+	if !ok {
+		return resource, nil
+	}
+
+	values := make(map[string]interface{}, len(pointers))
+	for name, pointer := range pointers {
+		if value, ok := jsonPointer(document, pointer); ok {
+			values[name] = value
+		}
+	}
+	for _, pointer := range pointers {
+		removePointer(object, strings.Split(strings.TrimPrefix(pointer, "/"), "/"))
+	}
+	for name, value := range values {
+		object[name] = value
+	}
+	return json.Marshal(object)
+}
+
+// removePointer removes the property at the tokens of a JSON pointer from the object, along
+// with the objects that contained it if they are left empty
+func removePointer(object map[string]interface{}, tokens []string) {
+	token := strings.ReplaceAll(strings.ReplaceAll(tokens[0], "~1", "/"), "~0", "~")
+	if len(tokens) == 1 {
+		delete(object, token)
+		return
+	}
+
+	nested, ok := object[token].(map[string]interface{})
+	if !ok {
+		return
+	}
+	removePointer(nested, tokens[1:])
+	if len(nested) == 0 {
+		delete(object, token)
+	}
+}
+
+// requestMediaType returns the media type of request bodies of the operation
+func (op *Operation) requestMediaType() string {
+	if op.RequestMediaType != "" {
+		return op.RequestMediaType
+	}
+	return op.MediaType
+}
+
+// isFormMediaType reports whether request bodies of the media type are sent as form fields
+func isFormMediaType(mediaType string) bool {
+	return mediaType == "application/x-www-form-urlencoded" || mediaType == "multipart/form-data"
+}
+
+// formValues adds the form fields of a decoded JSON value to the values. Object properties are
+// named with brackets, like "metadata[key]", and each element of an array is sent as a field
+// of the same name.
+func formValues(values url.Values, name string, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+	case map[string]interface{}:
+		for key, nested := range v {
+			if name != "" {
+				key = name + "[" + key + "]"
+			}
+			formValues(values, key, nested)
+		}
+	case []interface{}:
+		for _, element := range v {
+			formValues(values, name, element)
+		}
+	case float64:
+		values.Add(name, strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		values.Add(name, fmt.Sprint(v))
+	}
+}
+
+// multipartBody encodes a body as multipart form data, in which the properties that are files
+// are uploaded from the paths they hold. The result includes the content type, which names
+// the boundary between the parts.
+func multipartBody(body interface{}, files []string) ([]byte, string, error) {
+	document, err := toDocument(body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	object, _ := document.(map[string]interface{})
+	values := make(url.Values)
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for _, name := range sortedKeys(object) {
+		path, ok := object[name].(string)
+		if !ok || !contains(files, name) {
+			formValues(values, name, object[name])
+			continue
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("could not read the %s file: %w", name, err)
+		}
+		part, err := writer.CreateFormFile(name, filepath.Base(path))
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(contents); err != nil {
+			return nil, "", err
+		}
+	}
+
+	for _, name := range sortedKeys(values) {
+		for _, value := range values[name] {
+			if err := writer.WriteField(name, value); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+// encodeBody encodes a request body in the request media type of the operation. The result
+// includes the content type of the encoded body.
+func encodeBody(op *Operation, body interface{}) ([]byte, string, error) {
+	mediaType := op.requestMediaType()
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		document, err := toDocument(body)
+		if err != nil {
+			return nil, "", err
+		}
+		values := make(url.Values)
+		formValues(values, "", document)
+		return []byte(values.Encode()), mediaType, nil
+	case "multipart/form-data":
+		return multipartBody(body, op.Files)
+	}
+
+	encoded, err := json.Marshal(body)
+	return encoded, mediaType, err
+}
+
+// unwrap returns the encoded value located by a JSON pointer within an encoded JSON document
+func unwrap(pointer string, body []byte) ([]byte, error) {
+	var document interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, err
+	}
+
+	value, ok := jsonPointer(document, pointer)
+	if !ok {
+		return nil, fmt.Errorf("%s not found", pointer)
+	}
+	return json.Marshal(value)
+}
+
+// patch returns the partial update from the prior body to the body, in the format of the
+// operation, within the envelope. JSON:API resource objects are patched by the JSON:API.
+func patch(format, envelope string, jsonAPI *JSONAPI, prior, body interface{}) (interface{}, error) {
+	priorDocument, err := toDocument(prior)
+	if err != nil {
+		return nil, err
+	}
+	document, err := toDocument(body)
+	if err != nil {
+		return nil, err
+	}
+
+	priorObject, priorOK := priorDocument.(map[string]interface{})
+	object, ok := document.(map[string]interface{})
+
+	switch {
+	case format == "json" && priorOK && ok:
+		return jsonPatch(priorObject, object, envelope), nil
+	case format == "json":
+		return []interface{}{map[string]interface{}{"op": "replace", "path": envelope, "value": document}}, nil
+	case priorOK && ok && jsonAPI != nil:
+		return envelop(envelope, jsonAPI.patch(priorObject, object)), nil
+	case priorOK && ok:
+		return envelop(envelope, mergePatch(priorObject, object)), nil
+	default:
+		return envelop(envelope, document), nil
+	}
+}
+
+// mergePatch returns the JSON Merge Patch of the changes from the prior object to the object,
+// in which removed properties are null
+func mergePatch(prior, object map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for name, value := range object {
+		priorValue, ok := prior[name]
+		if ok && reflect.DeepEqual(priorValue, value) {
+			continue
+		}
+
+		priorNested, priorOK := priorValue.(map[string]interface{})
+		nested, ok := value.(map[string]interface{})
+		if priorOK && ok {
+			result[name] = mergePatch(priorNested, nested)
+		} else {
+			result[name] = value
+		}
+	}
+
+	for name := range prior {
+		if _, ok := object[name]; !ok {
+			result[name] = nil
+		}
+	}
+	return result
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// jsonPatch returns the JSON Patch operations that change the prior object to the object, at
+// the JSON pointer prefix
+func jsonPatch(prior, object map[string]interface{}, prefix string) []interface{} {
+	result := make([]interface{}, 0)
+	for _, name := range sortedKeys(object) {
+		path := prefix + "/" + pointerEscaper.Replace(name)
+		value := object[name]
+
+		priorValue, ok := prior[name]
+		switch {
+		case !ok:
+			result = append(result, map[string]interface{}{"op": "add", "path": path, "value": value})
+		case reflect.DeepEqual(priorValue, value):
+			continue
+		default:
+			priorNested, priorOK := priorValue.(map[string]interface{})
+			nested, ok := value.(map[string]interface{})
+			if priorOK && ok {
+				result = append(result, jsonPatch(priorNested, nested, path)...)
+			} else {
+				result = append(result, map[string]interface{}{"op": "replace", "path": path, "value": value})
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(prior) {
+		if _, ok := object[name]; !ok {
+			result = append(result, map[string]interface{}{"op": "remove", "path": prefix + "/" + pointerEscaper.Replace(name)})
+		}
+	}
+	return result
+}
+
+// jsonPointer resolves a JSON pointer, like "/status/state", within a decoded JSON document
+func jsonPointer(document interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return document, true
+	}
+
+	current := document
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch value := current.(type) {
+		case map[string]interface{}:
+			next, ok := value[token]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(value) {
+				return nil, false
+			}
+			current = value[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// contains reports whether a status value is one of the values
+func contains(values []string, value interface{}) bool {
+	s := fmt.Sprint(value)
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// statusURL finds the URL of the status of an accepted operation, resolved against the URL of
+// the operation. The result is empty if the response does not include it.
+func statusURL(async *Async, u string, resp *http.Response, body []byte) (string, error) {
+	location := ""
+	if async.Header != "" {
+		location = resp.Header.Get(async.Header)
+	}
+
+	if location == "" && async.URLPointer != "" && len(bytes.TrimSpace(body)) > 0 {
+		var document interface{}
+		if err := json.Unmarshal(body, &document); err != nil {
+			return "", fmt.Errorf("could not decode accepted response: %w", err)
+		}
+		if value, ok := jsonPointer(document, async.URLPointer); ok {
+			location, _ = value.(string)
+		}
+	}
+
+	if location == "" {
+		return "", nil
+	}
+
+	base, err := url.Parse(u)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := base.Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("invalid status URL %q: %w", location, err)
+	}
+	return resolved.String(), nil
+}
+
+// wait polls the status of an accepted operation until it completes, fails, or the context
+// is done, and returns the last status response
+func (c *Client) wait(ctx context.Context, op *Operation, auths []Authenticator, u string, resp *http.Response, body []byte) (*http.Response, []byte, error) {
+	target, err := statusURL(op.Async, u, resp, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Without a status URL, a deleted resource is polled until it is gone
+	gone := false
+	if target == "" {
+		if op.Method != http.MethodDelete {
+			return nil, nil, fmt.Errorf("%s %s was accepted, but the response did not include a status URL", op.Method, op.Path)
+		}
+		target = u
+		gone = true
+	}
+
+	poll := &Operation{
+		Method:    http.MethodGet,
+		Path:      op.Path,
+		MediaType: op.MediaType,
+		Retry:     op.Retry,
+	}
+
+	for {
+		wait := op.Async.Interval
+		if after, ok := retryAfter(resp); ok {
+			wait = after
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, nil, fmt.Errorf("timed out waiting for %s %s to complete: %w", op.Method, op.Path, ctx.Err())
+		case <-timer.C:
+		}
+
+		if resp, body, err = c.sendWithRetries(ctx, poll, auths, target, NewRequest(), nil); err != nil {
+			return nil, nil, err
+		}
+
+		switch {
+		case gone && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone):
+			resp.StatusCode = http.StatusNoContent
+			return resp, nil, nil
+		case gone:
+			continue
+		case resp.StatusCode < 200 || resp.StatusCode > 299:
+			return resp, body, nil
+		case op.Async.StatusPointer != "":
+			var document interface{}
+			if err := json.Unmarshal(body, &document); err != nil {
+				return nil, nil, fmt.Errorf("could not decode status response: %w", err)
+			}
+
+			status, _ := jsonPointer(document, op.Async.StatusPointer)
+			if contains(op.Async.Success, status) {
+				return resp, body, nil
+			}
+			if contains(op.Async.Failure, status) {
+				return nil, nil, &AsyncError{Method: op.Method, Path: op.Path, Status: fmt.Sprint(status), Body: body}
+			}
+		case resp.StatusCode != http.StatusAccepted:
+			return resp, body, nil
+		}
+	}
+}
+
+// Do calls the API operation. If the response has a body and result is not nil, the
+// body is decoded into result.
+func (c *Client) Do(ctx context.Context, op *Operation, req *Request, result interface{}) (*Response, error) {
+	// Parameters set by the resource override those configured for the provider
+	for _, param := range op.Parameters {
+		if value, ok := c.Parameters[param]; ok && !req.Has(param.In, param.Name) {
+			req.Set(param.In, param.Name, value)
+		}
+	}
+	for param, value := range c.GlobalParameters {
+		if !req.Has(param.In, param.Name) {
+			req.Set(param.In, param.Name, value)
+		}
+	}
+
+	// The same key is sent with each retry, so the API only processes the call once
+	if op.IdempotencyKey != "" && !req.Has("header", op.IdempotencyKey) {
+		key, err := newIdempotencyKey()
+		if err != nil {
+			return nil, fmt.Errorf("could not generate idempotency key: %w", err)
+		}
+		req.Set("header", op.IdempotencyKey, key)
+	}
+
+	u, err := c.url(op, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var encoded []byte
+	if req.Body != nil {
+		body, prior := req.Body, req.Prior
+		if len(op.Pointers) > 0 {
+			if body, err = nest(body, op.Pointers); err != nil {
+				return nil, fmt.Errorf("could not encode request body: %w", err)
+			}
+			if prior != nil {
+				if prior, err = nest(prior, op.Pointers); err != nil {
+					return nil, fmt.Errorf("could not encode prior request body: %w", err)
+				}
+			}
+		}
+
+		// Form fields are the attributes of the resource, while JSON documents may wrap them
+		envelope, jsonAPI := op.Envelope, op.JSONAPI
+		if isFormMediaType(op.requestMediaType()) {
+			envelope, jsonAPI = "", nil
+		}
+
+		if jsonAPI != nil {
+			if body, err = jsonAPI.encode(body); err != nil {
+				return nil, fmt.Errorf("could not encode request body: %w", err)
+			}
+			if prior != nil {
+				if prior, err = jsonAPI.encode(prior); err != nil {
+					return nil, fmt.Errorf("could not encode prior request body: %w", err)
+				}
+			}
+		}
+
+		if op.Patch != "" && prior != nil {
+			if body, err = patch(op.Patch, envelope, jsonAPI, prior, body); err != nil {
+				return nil, fmt.Errorf("could not determine changes: %w", err)
+			}
+		} else {
+			body = envelop(envelope, body)
+		}
+
+		var contentType string
+		if encoded, contentType, err = encodeBody(op, body); err != nil {
+			return nil, fmt.Errorf("could not encode request body: %w", err)
+		}
+		req.Set("header", "Content-Type", contentType)
+	}
+
+	auths, err := c.authenticators(op)
+	if err != nil {
+		return nil, err
+	}
+
+	httpResp, respBody, err := c.sendWithRetries(ctx, op, auths, u, req, encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	// Credentials that were rejected, such as an expired access token, are fetched again
+	// and the request is retried once.
+	if httpResp.StatusCode == http.StatusUnauthorized && invalidate(auths) {
+		if httpResp, respBody, err = c.sendWithRetries(ctx, op, auths, u, req, encoded); err != nil {
+			return nil, err
+		}
+	}
+
+	accepted := httpResp.StatusCode == http.StatusAccepted && op.Async != nil
+	if accepted {
+		status, statusBody, err := c.wait(ctx, op, auths, u, httpResp, respBody)
+		if err != nil {
+			return nil, err
+		}
+
+		// The status responses describe the operation rather than the resource, so they are
+		// not decoded into the result, unless the operation failed. The resource is read once
+		// the operation completes.
+		if status.StatusCode < 200 || status.StatusCode > 299 {
+			httpResp, respBody = status, statusBody
+		}
+	}
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode > 299 {
+		apiErr := &APIError{
+			StatusCode: httpResp.StatusCode,
+			Status:     httpResp.Status,
+			Body:       respBody,
+		}
+		if op.Errors != nil {
+			apiErr.decode(op.Errors)
+		}
+		return nil, apiErr
+	}
+
+	if result != nil && len(bytes.TrimSpace(respBody)) > 0 {
+		resource := respBody
+		if op.Envelope != "" {
+			if resource, err = unwrap(op.Envelope, respBody); err != nil {
+				return nil, fmt.Errorf("could not decode response body: %w", err)
+			}
+		}
+		if op.JSONAPI != nil {
+			if resource, err = op.JSONAPI.decode(resource); err != nil {
+				return nil, fmt.Errorf("could not decode response body: %w", err)
+			}
+		}
+		if len(op.Pointers) > 0 {
+			if resource, err = flatten(resource, op.Pointers); err != nil {
+				return nil, fmt.Errorf("could not decode response body: %w", err)
+			}
+		}
+		if err := json.Unmarshal(resource, result); err != nil {
+			return nil, fmt.Errorf("could not decode response body: %w", err)
+		}
+	}
+
+	return &Response{
+		StatusCode: httpResp.StatusCode,
+		Header:     httpResp.Header,
+		Body:       respBody,
+		Accepted:   accepted,
+	}, nil
+}
+`
+}
+
+func (g *ClientGenerator) PackageName() string {
+	return g.Config.Provider.PackageName
+}
+
+func (g *ClientGenerator) CreateTemplateData() interface{} {
+	return &ClientGeneratorData{
+		PackageName: g.PackageName(),
+	}
+}
+
+func (g *ClientGenerator) Generate(destinationDirectory string) error {
+	return execute(g, fmt.Sprintf("%s/client.go", destinationDirectory))
+}
+
+func NewClientGenerator(doc *openapi3.T, config *config.Config) *ClientGenerator {
+	return &ClientGenerator{
+		Doc:    doc,
+		Config: config,
+	}
+}
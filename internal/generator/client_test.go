@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brandonc/tfpgen/internal/config"
+)
+
+const clientTests = "testdata/client/client_test.go"
+
+// testNames returns the names of the test functions in the files
+func testNames(t *testing.T, paths ...string) []string {
+	t.Helper()
+
+	result := make([]string, 0)
+	for _, path := range paths {
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			t.Fatalf("invalid tests: %s", err)
+		}
+
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && strings.HasPrefix(fn.Name.Name, "Test") {
+				result = append(result, fn.Name.Name)
+			}
+		}
+	}
+	return result
+}
+
+// runTests runs each test of the compiled test binary as a subtest
+func runTests(t *testing.T, binary string, names []string) {
+	t.Helper()
+
+	for _, name := range names {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			cmd := exec.Command(binary, "-test.run", "^"+name+"$")
+			if output, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("%s\n\n%s", err, output)
+			}
+		})
+	}
+}
+
+// buildClientTests renders the client into a module with the client tests, and returns the
+// path of the compiled test binary
+func buildClientTests(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	generator := NewClientGenerator(nil, &config.Config{
+		Provider: config.ProviderConfig{PackageName: "provider"},
+	})
+	if err := generator.Generate(dir); err != nil {
+		t.Fatalf("could not generate client: %s", err)
+	}
+
+	tests, err := os.ReadFile(clientTests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, "client_test.go"), tests, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/provider\n\ngo 1.19\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	binary := filepath.Join(dir, "client.test")
+	cmd := exec.Command("go", "test", "-c", "-o", binary)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("could not build client tests: %s\n\n%s", err, output)
+	}
+	return binary
+}
+
+func Test_ClientTemplate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the generated client")
+	}
+
+	runTests(t, buildClientTests(t), testNames(t, clientTests))
+}
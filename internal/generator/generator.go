@@ -41,6 +41,12 @@ func GenerateAll(basePath string, doc *openapi3.T, config *config.Config) error
 		return fmt.Errorf("could not generate provider: %w", err)
 	}
 
+	clientGenerator := NewClientGenerator(doc, config)
+	err = clientGenerator.Generate(fmt.Sprintf("%s/provider", basePath))
+	if err != nil {
+		return fmt.Errorf("could not generate client: %w", err)
+	}
+
 	resourceGenerator := NewResourceGenerator(doc, config)
 	err = resourceGenerator.Generate(fmt.Sprintf("%s/provider", basePath))
 	if err != nil {
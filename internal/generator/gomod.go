@@ -14,6 +14,9 @@ type ModuleGenerator struct {
 
 type ModuleGeneratorData struct {
 	Repository string
+
+	// Whether any resource has a timeouts attribute
+	UsesTimeouts bool
 }
 
 var _ Generator = (*ModuleGenerator)(nil)
@@ -25,14 +28,23 @@ go 1.19
 
 require (
 	github.com/hashicorp/terraform-plugin-framework v1.1.1
+	{{- if .UsesTimeouts }}
+	github.com/hashicorp/terraform-plugin-framework-timeouts v0.3.1
+	{{- end }}
 	github.com/hashicorp/terraform-plugin-log v0.8.0
 )
 `
 }
 
 func (g *ModuleGenerator) CreateTemplateData() interface{} {
+	usesTimeouts := false
+	for _, res := range g.Config.Output {
+		usesTimeouts = usesTimeouts || (res.TfType == config.TfTypeResource && res.Timeouts != nil)
+	}
+
 	return &ModuleGeneratorData{
-		Repository: g.Config.Provider.ModuleRepository,
+		Repository:   g.Config.Provider.ModuleRepository,
+		UsesTimeouts: usesTimeouts,
 	}
 }
 
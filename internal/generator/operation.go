@@ -0,0 +1,321 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/brandonc/tfpgen/internal/config"
+	"github.com/brandonc/tfpgen/pkg/restutils"
+)
+
+// TemplateOperation describes the API operation bound to a resource action
+type TemplateOperation struct {
+	// The name of the generated Operation variable
+	VarName string
+
+	// The HTTP method of the operation
+	Method string
+
+	// The path template of the operation, like "/boards/{boardId}"
+	Path string
+
+	// The media type of response bodies, and of request bodies unless RequestMediaType is set
+	MediaType string
+
+	// The media type of request bodies, if it is not MediaType
+	RequestMediaType string
+
+	// The properties of multipart request bodies that are uploaded from file paths
+	Files []string
+
+	// The JSON pointers to the nested properties of the resource, keyed by the attribute API
+	// names mapped to them
+	Pointers map[string]string
+
+	// The parameters sent with each request and the attributes that supply them
+	Parameters []*TemplateParameter
+
+	// The provider parameters the operation declares, which have no DataName
+	ProviderParameters []*TemplateParameter
+
+	// Whether the security requirements are known. If not, all credentials are sent.
+	SecuritySpecified bool
+
+	// The alternative security requirements of the operation
+	Security []*TemplateSecurityRequirement
+
+	// The transient response status codes that are retried
+	Retry []int
+
+	// How to wait for the operation if it responds with 202 Accepted, or nil
+	Async *TemplateAsync
+
+	// The response status codes that indicate the resource does not exist
+	NotFound []int
+
+	// The JSON pointers to the parts of documented error response bodies, or nil
+	Errors *restutils.RESTErrorSchema
+
+	// How partial updates are sent, or nil if the request body replaces the resource
+	Patch *restutils.RESTPatch
+
+	// Whether the operation is conditional on the entity tag of the resource
+	IfMatch bool
+
+	// The name of the header that identifies each call, or empty if none is sent
+	IdempotencyKey string
+
+	// The JSON pointer to the resource within request and response bodies, or empty
+	Envelope string
+
+	// The JSON:API resource object within the envelope, or nil
+	JSONAPI *restutils.RESTJSONAPI
+}
+
+// TemplateAsync describes how to poll the status of an operation that was accepted
+type TemplateAsync struct {
+	Header        string
+	URLPointer    string
+	StatusPointer string
+	Success       []string
+	Failure       []string
+
+	// The Go expression of the time between polls, like "5 * time.Second"
+	Interval string
+}
+
+// templateAsync describes how to wait for an asynchronous operation. The result is nil if the
+// operation is not asynchronous.
+func templateAsync(async *config.AsyncConfig) (*TemplateAsync, error) {
+	if async == nil {
+		return nil, nil
+	}
+
+	if err := async.Validate(); err != nil {
+		return nil, err
+	}
+
+	interval, _ := async.PollInterval()
+
+	return &TemplateAsync{
+		Header:        async.StatusHeader(),
+		URLPointer:    async.URLPointer,
+		StatusPointer: async.StatusPointer,
+		Success:       async.Success,
+		Failure:       async.Failure,
+		Interval:      durationExpression(interval),
+	}, nil
+}
+
+// durationExpression formats a duration as a Go expression, like "5 * time.Second"
+func durationExpression(d time.Duration) string {
+	switch {
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%d * time.Minute", d/time.Minute)
+	case d%time.Second == 0:
+		return fmt.Sprintf("%d * time.Second", d/time.Second)
+	}
+	return fmt.Sprintf("%d * time.Millisecond", d/time.Millisecond)
+}
+
+// TemplateSecurityRequirement lists the security schemes an operation requires together
+type TemplateSecurityRequirement struct {
+	Schemes []*TemplateSecurityScope
+}
+
+// TemplateSecurityScope is a security scheme and the scopes it must grant
+type TemplateSecurityScope struct {
+	Name   string
+	Scopes []string
+}
+
+// templateSecurity describes the security requirements of an operation, warning if none of
+// them can be satisfied by the configured security schemes
+func templateSecurity(resource *restutils.RESTResource, action *restutils.RESTAction, configured map[string]*config.SecuritySchemeConfig) []*TemplateSecurityRequirement {
+	requirements := resource.GetSecurity(action)
+	result := make([]*TemplateSecurityRequirement, 0, len(requirements))
+	satisfiable := len(requirements) == 0
+
+	for _, requirement := range requirements {
+		names := make([]string, 0, len(requirement))
+		for name := range requirement {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		templateRequirement := &TemplateSecurityRequirement{
+			Schemes: make([]*TemplateSecurityScope, 0, len(names)),
+		}
+
+		configuredAll := true
+		for _, name := range names {
+			_, ok := configured[name]
+			configuredAll = configuredAll && ok
+			templateRequirement.Schemes = append(templateRequirement.Schemes, &TemplateSecurityScope{
+				Name:   name,
+				Scopes: requirement[name],
+			})
+		}
+
+		satisfiable = satisfiable || configuredAll
+		result = append(result, templateRequirement)
+	}
+
+	if !satisfiable {
+		fmt.Printf("warning: no configured security scheme satisfies the security requirements of %s %s\n", action.Method, action.Path)
+	}
+	return result
+}
+
+// TemplateParameter binds an operation parameter to the attribute that supplies its value
+type TemplateParameter struct {
+	// The OpenAPI name of the parameter
+	Name string
+
+	// Where the parameter is sent: path, query, header, or cookie
+	In restutils.In
+
+	// The data field name of the attribute that supplies the parameter value
+	DataName string
+
+	// The name of the generated data variable that holds the attribute
+	Source string
+}
+
+// findAttribute finds a top level attribute by OpenAPI name and location
+func findAttribute(attributes []*TemplateResourceAttribute, apiName string, in restutils.In) *TemplateResourceAttribute {
+	for _, att := range attributes {
+		if att.ApiName == apiName && att.In == in {
+			return att
+		}
+	}
+	return nil
+}
+
+// findIdentityAttribute finds the attribute whose value identifies the resource: the identity
+// attribute if one is known, otherwise the attribute that supplies the last path parameter
+// of the read action.
+func findIdentityAttribute(resource *restutils.RESTResource, attributes []*TemplateResourceAttribute) *TemplateResourceAttribute {
+	if resource.Identity != nil {
+		for _, att := range attributes {
+			if att.ApiName == resource.Identity.Attribute && att.In == restutils.InContent {
+				return att
+			}
+		}
+	}
+
+	if resource.RESTShow != nil {
+		params := restutils.PathParameters(resource.RESTShow.Path)
+		if len(params) > 0 {
+			return findAttribute(attributes, params[len(params)-1], restutils.InPath)
+		}
+	}
+	return nil
+}
+
+// templateOperation binds each parameter of an action to the attribute that supplies it. Path
+// parameters must be supplied by an attribute, while other parameters are only sent if they
+// were exposed as attributes or configured for the provider.
+//
+// The security requirements are only specified if security schemes are configured by name.
+func templateOperation(varName string, resource *restutils.RESTResource, action *restutils.RESTAction, mediaType string, attributes []*TemplateResourceAttribute, api *config.ApiConfig) (*TemplateOperation, error) {
+	providerParameters, err := api.ParameterBindings()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TemplateOperation{
+		VarName:            varName,
+		Method:             strings.ToUpper(action.Method),
+		Path:               action.Path,
+		MediaType:          resource.ProbeForResponseMediaType(action, mediaType),
+		Parameters:         make([]*TemplateParameter, 0),
+		ProviderParameters: make([]*TemplateParameter, 0),
+		SecuritySpecified:  len(api.SecuritySchemes) > 0,
+		Retry:              resource.GetRetryStatusCodes(action),
+		Errors:             resource.ProbeForErrorSchema(action),
+		Patch:              resource.ProbeForPatch(action),
+		IfMatch:            resource.ProbeForIfMatch(action),
+		Envelope:           resource.Envelope,
+		JSONAPI:            resource.ProbeForJSONAPI(mediaType),
+	}
+
+	if result.SecuritySpecified {
+		result.Security = templateSecurity(resource, action, api.SecuritySchemes)
+	}
+
+	if len(result.Retry) == 0 {
+		if result.Retry, err = api.DefaultRetryStatusCodes(); err != nil {
+			return nil, err
+		}
+	}
+
+	requestMediaType := resource.ProbeForRequestMediaType(action, mediaType)
+	if result.Patch != nil && result.Patch.MediaType != "" {
+		requestMediaType = result.Patch.MediaType
+	}
+	if requestMediaType != "" && requestMediaType != result.MediaType {
+		result.RequestMediaType = requestMediaType
+	}
+	result.Files = resource.ProbeForFiles(action, requestMediaType)
+
+	for _, att := range attributes {
+		if att.Pointer == "" {
+			continue
+		}
+		if result.Pointers == nil {
+			result.Pointers = make(map[string]string)
+		}
+		result.Pointers[att.ApiName] = att.Pointer
+	}
+
+	for _, name := range restutils.PathParameters(action.Path) {
+		var att *TemplateResourceAttribute
+		if resource.Identity != nil && resource.Identity.Parameter == name {
+			att = findIdentityAttribute(resource, attributes)
+		} else {
+			att = findAttribute(attributes, name, restutils.InPath)
+		}
+
+		if att == nil {
+			return nil, fmt.Errorf("path parameter \"%s\" of %s %s is not supplied by any attribute", name, action.Method, action.Path)
+		}
+
+		result.Parameters = append(result.Parameters, &TemplateParameter{
+			Name:     name,
+			In:       restutils.InPath,
+			DataName: att.DataName,
+			Source:   "data",
+		})
+	}
+
+	for _, paramRef := range resource.GetParameters(action) {
+		param := paramRef.Value
+		if param.In == string(restutils.InPath) {
+			continue
+		}
+
+		if att := findAttribute(attributes, param.Name, restutils.In(param.In)); att != nil {
+			result.Parameters = append(result.Parameters, &TemplateParameter{
+				Name:     param.Name,
+				In:       restutils.In(param.In),
+				DataName: att.DataName,
+				Source:   "data",
+			})
+		}
+
+		for _, binding := range providerParameters {
+			if binding.In == restutils.In(param.In) && restutils.SameParameterName(binding.In, binding.Name, param.Name) {
+				// The configured name is the one the provider uses to identify the parameter
+				result.ProviderParameters = append(result.ProviderParameters, &TemplateParameter{
+					Name: binding.Name,
+					In:   binding.In,
+				})
+			}
+		}
+	}
+
+	return result, nil
+}
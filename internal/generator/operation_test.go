@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/brandonc/tfpgen/internal/config"
+	"github.com/brandonc/tfpgen/pkg/restutils"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const parametersSpec = `
+openapi: 3.0.1
+info:
+  title: Test Provider Parameters
+  version: "1"
+paths:
+  /boards/{boardId}:
+    get:
+      parameters:
+        - in: path
+          name: boardId
+          required: true
+          schema:
+            type: string
+        - in: query
+          name: Region
+          schema:
+            type: string
+        - in: header
+          name: x-tenant
+          schema:
+            type: string
+      responses:
+        "200":
+          description: Success
+`
+
+func Test_templateOperationProviderParameters(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(parametersSpec))
+	if err != nil {
+		t.Fatalf("invalid spec: %s", err)
+	}
+
+	probe := restutils.NewProbe(doc)
+	resource, ok := probe.ProbeForResources()["Boards"]
+	if !ok {
+		t.Fatal("expected \"Boards\" resource")
+	}
+	action := &restutils.RESTAction{Name: restutils.Show, Method: http.MethodGet, Path: "/boards/{boardId}"}
+	attributes := []*TemplateResourceAttribute{{ApiName: "boardId", In: restutils.InPath, DataName: "BoardId"}}
+
+	api := &config.ApiConfig{
+		Parameters: []config.ParameterConfig{
+			{Name: "region", In: "query"},
+			{Name: "X-Tenant", In: "header"},
+		},
+	}
+
+	operation, err := templateOperation("resourceBoardsRead", resource, action, "application/json", attributes, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only header names are case insensitive, so the Region query parameter is another parameter
+	if len(operation.ProviderParameters) != 1 || operation.ProviderParameters[0].Name != "X-Tenant" {
+		t.Errorf("expected only the X-Tenant header to be bound to the provider, got %v", operation.ProviderParameters)
+	}
+}
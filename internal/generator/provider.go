@@ -2,14 +2,21 @@ package generator
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/brandonc/tfpgen/internal/config"
+	"github.com/brandonc/tfpgen/pkg/naming"
+	"github.com/brandonc/tfpgen/pkg/restutils"
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
 type ProviderGenerator struct {
 	Config *config.Config
 	Doc    *openapi3.T
+
+	data *ProviderResourceData
 }
 
 var _ Generator = (*ProviderGenerator)(nil)
@@ -18,18 +25,123 @@ type ProviderResourceData struct {
 	DefaultEndpoint string
 	PackageName     string
 	ProviderName    string
+	Attributes      []*TemplateProviderAttribute
+	Parameters      []*TemplateProviderParameter
+	ServerVariables []*TemplateServerVariable
+	Settings        []*TemplateProviderSetting
+	SecuritySchemes []*TemplateSecurityScheme
 	Resources       []*config.TerraformResource
 	DataSources     []*config.TerraformResource
 }
 
+// TemplateProviderAttribute describes a string, bool, number, or string list attribute of the
+// provider schema
+type TemplateProviderAttribute struct {
+	TfName      string
+	DataName    string
+	Description string
+	Sensitive   bool
+
+	// The schema attribute type, which is a StringAttribute if empty
+	Schema FrameworkAttributeSchemaString
+
+	// The environment variable the attribute is read from when not configured
+	EnvVar string
+
+	// The Go expression of the value used when the attribute is not configured or set in the
+	// environment. For lists, the expression also returns diagnostics.
+	Default string
+}
+
+// SchemaAttribute returns the schema attribute type
+func (a *TemplateProviderAttribute) SchemaAttribute() FrameworkAttributeSchemaString {
+	if len(a.Schema) == 0 {
+		return SchemaString
+	}
+	return a.Schema
+}
+
+// ValueType returns the name of the value type in the framework types package, like "String"
+func (a *TemplateProviderAttribute) ValueType() string {
+	return strings.TrimSuffix(string(a.SchemaAttribute()), "Attribute")
+}
+
+// TypeDescription describes the value type for error messages, like "a boolean"
+func (a *TemplateProviderAttribute) TypeDescription() string {
+	switch a.SchemaAttribute() {
+	case SchemaBool:
+		return "a boolean"
+	case SchemaInt64:
+		return "an integer"
+	case SchemaFloat64:
+		return "a number"
+	case SchemaList:
+		return "a list"
+	}
+	return "a string"
+}
+
+// List reports whether the attribute is a string list
+func (a *TemplateProviderAttribute) List() bool {
+	return a.SchemaAttribute() == SchemaList
+}
+
+// TemplateServerVariable describes a variable of the default endpoint, which is configured by a
+// provider attribute
+type TemplateServerVariable struct {
+	Attribute *TemplateProviderAttribute
+
+	// The variable as it appears in the endpoint, like {region}
+	Placeholder string
+
+	// The allowed values, as a list of Go string literals and as a list for messages
+	EnumCases string
+	EnumList  string
+}
+
+// TemplateProviderSetting describes a custom provider attribute, whose value is available to the
+// client and may also be sent with every request or applied as a client option
+type TemplateProviderSetting struct {
+	Attribute *TemplateProviderAttribute
+
+	// The parameter the value is sent as with every request, if any
+	In        restutils.In
+	Parameter string
+
+	Option config.ClientOption
+}
+
+// TemplateProviderParameter describes a parameter common to most operations, which is
+// configured by a provider attribute
+type TemplateProviderParameter struct {
+	Attribute *TemplateProviderAttribute
+
+	// The OpenAPI name of the parameter
+	Name string
+
+	// Where the parameter is sent: query, header, or cookie
+	In restutils.In
+}
+
 func (g *ProviderGenerator) Template() string {
 	return `// Code generated by tfpgen; DO NOT EDIT.
 package {{ .PackageName }}
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	{{- if .UsesEnv }}
+	"os"
+	{{- end }}
+	"strconv"
+	{{- if or .UsesListEnv .ServerVariables }}
+	"strings"
+	{{- end }}
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -42,8 +154,9 @@ type Provider struct {
 }
 
 type providerData struct {
-	ApiToken types.String ` + "`tfsdk:\"api_token\"`" + `
-	Endpoint types.String ` + "`tfsdk:\"endpoint\"`" + `
+	{{- range .Attributes }}
+	{{ .DataName }} types.{{ .ValueType }} ` + "`tfsdk:\"{{ .TfName }}\"`" + `
+	{{- end }}
 }
 
 func (p *Provider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -54,14 +167,16 @@ func (p *Provider) Metadata(ctx context.Context, req provider.MetadataRequest, r
 func (p *Provider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
-			"endpoint": schema.StringAttribute{
-				MarkdownDescription: "The HTTP API endpoint for the provider",
-				Optional:            true,
-			},
-			"api_token": schema.StringAttribute{
-				MarkdownDescription: "The HTTP API token, sent as Authorization: Bearer header",
+			{{- range .Attributes }}
+			"{{ .TfName }}": schema.{{ .SchemaAttribute }}{
+				{{- if .List }}
+				ElementType:         types.StringType,
+				{{- end }}
+				MarkdownDescription: "{{ .Description }}",
 				Optional:            true,
+				Sensitive:           {{ .Sensitive }},
 			},
+			{{- end }}
 		},
 	}
 }
@@ -75,12 +190,187 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 		return
 	}
 
+	{{- range .Attributes }}
+	{{- if .EnvVar }}
+	if value, ok := os.LookupEnv("{{ .EnvVar }}"); ok && data.{{ .DataName }}.IsNull() {
+		{{- if .List }}
+		data.{{ .DataName }}, diags = types.ListValueFrom(ctx, types.StringType, strings.Fields(strings.ReplaceAll(value, ",", " ")))
+		resp.Diagnostics.Append(diags...)
+		{{- else if eq .ValueType "String" }}
+		data.{{ .DataName }} = types.StringValue(value)
+		{{- else }}
+		{{- if eq .ValueType "Bool" }}
+		parsed, err := strconv.ParseBool(value)
+		{{- else if eq .ValueType "Int64" }}
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		{{- else }}
+		parsed, err := strconv.ParseFloat(value, 64)
+		{{- end }}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Environment Variable",
+				"The {{ .EnvVar }} environment variable must be {{ .TypeDescription }}, got \""+value+"\".",
+			)
+		}
+		data.{{ .DataName }} = types.{{ .ValueType }}Value(parsed)
+		{{- end }}
+	}
+	{{- end }}
+	{{- if .Default }}
+	if data.{{ .DataName }}.IsNull() {
+		{{- if .List }}
+		data.{{ .DataName }}, diags = {{ .Default }}
+		resp.Diagnostics.Append(diags...)
+		{{- else }}
+		data.{{ .DataName }} = {{ .Default }}
+		{{- end }}
+	}
+	{{- end }}
+	{{- end }}
+	{{- range .ServerVariables }}
+	{{- if .EnumCases }}
+
+	if !data.{{ .Attribute.DataName }}.IsNull() {
+		switch data.{{ .Attribute.DataName }}.ValueString() {
+		case {{ .EnumCases }}:
+		default:
+			resp.Diagnostics.AddAttributeError(
+				path.Root("{{ .Attribute.TfName }}"),
+				"Invalid Server Variable",
+				"The {{ .Attribute.TfName }} attribute must be one of: {{ .EnumList }}.",
+			)
+		}
+	}
+	{{- end }}
+	{{- end }}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if data.Endpoint.IsNull() {
+		{{- if .RelativeEndpoint }}
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Missing Endpoint",
+			"The endpoint attribute is required, because the API only documents the relative server URL {{ .DefaultEndpoint }}.",
+		)
+		return
+		{{- else }}
 		data.Endpoint = types.StringValue("{{ .DefaultEndpoint }}")
+		{{- end }}
+	}
+
+	endpoint := data.Endpoint.ValueString()
+	{{- range .ServerVariables }}
+	if strings.Contains(endpoint, "{{ .Placeholder }}") {
+		if data.{{ .Attribute.DataName }}.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("{{ .Attribute.TfName }}"),
+				"Missing Server Variable",
+				"The {{ .Attribute.TfName }} attribute is required by the endpoint "+endpoint+".",
+			)
+			return
+		}
+		endpoint = strings.ReplaceAll(endpoint, "{{ .Placeholder }}", data.{{ .Attribute.DataName }}.ValueString())
+	}
+	{{- end }}
+
+	client := NewClient(endpoint)
+	{{- range .SecuritySchemes }}
+	{{- if eq .Type "bearer_token" }}
+	if !data.{{ .Token.DataName }}.IsNull() {
+		client.Auth["{{ .Key }}"] = &BearerAuth{
+			Token: data.{{ .Token.DataName }}.ValueString(),
+		}
+	}
+	{{- else if eq .Type "api_key" }}
+	if !data.{{ .ApiKey.DataName }}.IsNull() {
+		client.Auth["{{ .Key }}"] = &APIKeyAuth{
+			In:    "{{ .In }}",
+			Name:  "{{ .Name }}",
+			Value: data.{{ .ApiKey.DataName }}.ValueString(),
+		}
+	}
+	{{- else if eq .Type "basic" }}
+	if !data.{{ .Username.DataName }}.IsNull() || !data.{{ .Password.DataName }}.IsNull() {
+		client.Auth["{{ .Key }}"] = &BasicAuth{
+			Username: data.{{ .Username.DataName }}.ValueString(),
+			Password: data.{{ .Password.DataName }}.ValueString(),
+		}
+	}
+	{{- else if eq .Type "oauth2_client_credentials" }}
+	if !data.{{ .ClientID.DataName }}.IsNull() {
+		if data.{{ .ClientSecret.DataName }}.IsNull() {
+			resp.Diagnostics.AddError(
+				"Missing OAuth2 Client Secret",
+				"The {{ .ClientSecret.TfName }} attribute is required when {{ .ClientID.TfName }} is configured.",
+			)
+			return
+		}
+
+		auth := &ClientCredentialsAuth{
+			TokenURL:     "{{ .TokenURL }}",
+			ClientID:     data.{{ .ClientID.DataName }}.ValueString(),
+			ClientSecret: data.{{ .ClientSecret.DataName }}.ValueString(),
+			Scopes:       []string{ {{- range $index, $scope := .Scopes }}{{ if $index }}, {{ end }}"{{ $scope }}"{{ end -}} },
+		}
+
+		if !data.{{ .ClientScopes.DataName }}.IsNull() {
+			auth.Scopes = nil
+			diags = data.{{ .ClientScopes.DataName }}.ElementsAs(ctx, &auth.Scopes, false)
+			resp.Diagnostics.Append(diags...)
+
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
+		client.Auth["{{ .Key }}"] = auth
 	}
+	{{- end }}
+	{{- end }}
+	{{- range .Parameters }}
+	if !data.{{ .Attribute.DataName }}.IsNull() {
+		client.Parameters[Parameter{In: "{{ .In }}", Name: "{{ .Name }}"}] = data.{{ .Attribute.DataName }}.ValueString()
+	}
+	{{- end }}
+	{{- range .Settings }}
+	if !data.{{ .Attribute.DataName }}.IsNull() {
+		{{- if .Attribute.List }}
+		var value []string
+		diags = data.{{ .Attribute.DataName }}.ElementsAs(ctx, &value, false)
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		{{- else }}
+		value := data.{{ .Attribute.DataName }}.Value{{ .Attribute.ValueType }}()
+		{{- end }}
+		client.Settings["{{ .Attribute.TfName }}"] = value
+		{{- if .Parameter }}
+		client.GlobalParameters[Parameter{In: "{{ .In }}", Name: "{{ .Parameter }}"}] = value
+		{{- end }}
+		{{- if eq .Option "insecure_skip_verify" }}
+		client.SetInsecureSkipVerify(value)
+		{{- else if eq .Option "max_retries" }}
+		client.MaxRetries = int(value)
+		{{- else if eq .Option "timeout" }}
+
+		if err := client.SetTimeout(value); err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Timeout",
+				"The {{ .Attribute.TfName }} attribute must be a duration, like \"30s\": "+err.Error(),
+			)
+			return
+		}
+		{{- end }}
+	}
+	{{- end }}
 
-	// If the upstream provider SDK or HTTP client requires configuration, such
-	// as authentication or logging, this is a great opportunity to do so.
+	resp.DataSourceData = client
+	resp.ResourceData = client
 
 	p.Configured = true
 }
@@ -110,6 +400,44 @@ func New(version string) func() provider.Provider {
 		}
 	}
 }
+
+// addErrorDiagnostics adds an error returned by the client to the diagnostics. Each field error
+// described by the API is also added at the path of the rejected attribute, which is found
+// using the attribute names keyed by API name.
+func addErrorDiagnostics(diags *diag.Diagnostics, attributes map[string]string, detail string, err error) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		for _, field := range apiErr.Fields {
+			if attributePath, ok := fieldAttributePath(attributes, field.Path); ok {
+				diags.AddAttributeError(attributePath, "Invalid Attribute Value", field.Message)
+			}
+		}
+	}
+
+	diags.AddError(ErrorSummary(err), fmt.Sprintf("%s, got error: %s", detail, err))
+}
+
+// fieldAttributePath returns the path of the attribute that corresponds to the path of a field
+// error. If only part of the field path is known, the path of the nearest enclosing attribute
+// is returned.
+func fieldAttributePath(attributes map[string]string, fieldPath []string) (path.Path, bool) {
+	result := path.Empty()
+	found := false
+	for _, name := range fieldPath {
+		if index, err := strconv.Atoi(name); err == nil && found {
+			result = result.AtListIndex(index)
+			continue
+		}
+
+		tfName, ok := attributes[name]
+		if !ok {
+			break
+		}
+		result = result.AtName(tfName)
+		found = true
+	}
+	return result, found
+}
 `
 }
 
@@ -118,6 +446,11 @@ func (g *ProviderGenerator) PackageName() string {
 }
 
 func (g *ProviderGenerator) CreateTemplateData() interface{} {
+	return g.data
+}
+
+// templateData describes the provider, its attributes, and its resources
+func (g *ProviderGenerator) templateData() (*ProviderResourceData, error) {
 	resources := make([]*config.TerraformResource, 0)
 	dataSources := make([]*config.TerraformResource, 0)
 
@@ -129,16 +462,305 @@ func (g *ProviderGenerator) CreateTemplateData() interface{} {
 			// dataSources = append(dataSources, res)
 		}
 	}
+
+	if _, err := g.Config.Api.ParameterBindings(); err != nil {
+		return nil, err
+	}
+
+	schemes, err := templateSecuritySchemes(&g.Config.Api)
+	if err != nil {
+		return nil, err
+	}
+
+	parameters := g.templateParameters()
+
+	variables, err := templateServerVariables(&g.Config.Api)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := []*TemplateProviderAttribute{{
+		TfName:      "endpoint",
+		DataName:    "Endpoint",
+		Description: "The HTTP API endpoint for the provider",
+	}}
+	for _, variable := range variables {
+		attributes = append(attributes, variable.Attribute)
+	}
+	for _, scheme := range schemes {
+		attributes = append(attributes, scheme.Attributes()...)
+	}
+	for _, parameter := range parameters {
+		attributes = append(attributes, parameter.Attribute)
+	}
+
+	settings, err := templateSettings(withClientOptions(g.Config.Provider.Attributes))
+	if err != nil {
+		return nil, err
+	}
+	for _, setting := range settings {
+		attributes = append(attributes, setting.Attribute)
+	}
+
+	names := make(map[string]bool)
+	for _, att := range attributes {
+		if names[att.TfName] {
+			return nil, fmt.Errorf("the provider attribute %s is defined more than once", att.TfName)
+		}
+		names[att.TfName] = true
+
+		if att.EnvVar = g.Config.Provider.EnvVar(att.TfName); len(att.EnvVar) > 0 {
+			att.Description = fmt.Sprintf("%s. May also be set with the `%s` environment variable.", strings.TrimSuffix(att.Description, "."), att.EnvVar)
+		}
+	}
+
+	for name := range g.Config.Provider.Env {
+		if !names[name] {
+			return nil, fmt.Errorf("provider env refers to the unknown attribute %s", name)
+		}
+	}
+
 	return &ProviderResourceData{
 		DefaultEndpoint: g.Config.Api.DefaultEndpoint,
 		PackageName:     g.PackageName(),
 		ProviderName:    g.Config.Provider.ProviderName(),
+		Attributes:      attributes,
+		Parameters:      parameters,
+		ServerVariables: variables,
+		Settings:        settings,
+		SecuritySchemes: schemes,
 		Resources:       resources,
 		DataSources:     dataSources,
+	}, nil
+}
+
+// RelativeEndpoint reports whether the default endpoint is only the path of a relative server
+// URL, so the endpoint must be configured
+func (d *ProviderResourceData) RelativeEndpoint() bool {
+	return !config.IsAbsoluteEndpoint(d.DefaultEndpoint)
+}
+
+// UsesEnv reports whether any attribute is read from the environment
+func (d *ProviderResourceData) UsesEnv() bool {
+	for _, att := range d.Attributes {
+		if len(att.EnvVar) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// UsesListEnv reports whether any list attribute is read from the environment
+func (d *ProviderResourceData) UsesListEnv() bool {
+	for _, att := range d.Attributes {
+		if att.List() && len(att.EnvVar) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// templateServerVariables describes the provider attributes of the default endpoint variables,
+// ordered by their position in the endpoint
+func templateServerVariables(api *config.ApiConfig) ([]*TemplateServerVariable, error) {
+	names, err := api.Variables()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*TemplateServerVariable, 0, len(names))
+	for _, name := range names {
+		variable := api.ServerVariables[name]
+
+		description := variable.Description
+		if len(description) == 0 {
+			description = fmt.Sprintf("The %s variable of the endpoint", name)
+		}
+
+		quoted := make([]string, 0, len(variable.Enum))
+		for _, value := range variable.Enum {
+			quoted = append(quoted, strconv.Quote(value))
+		}
+		if len(variable.Enum) > 0 {
+			description = fmt.Sprintf("%s. One of `%s`.", strings.TrimSuffix(description, "."), strings.Join(variable.Enum, "`, `"))
+		}
+
+		att := &TemplateProviderAttribute{
+			TfName:   naming.ToHCLName(name),
+			DataName: naming.ToTitleName(name),
+		}
+		if len(variable.Default) > 0 {
+			att.Default = fmt.Sprintf("types.StringValue(%s)", strconv.Quote(variable.Default))
+			description = fmt.Sprintf("%s. Defaults to `%s`.", strings.TrimSuffix(description, "."), variable.Default)
+		}
+		att.Description = strings.ReplaceAll(description, "\"", "\\\"")
+
+		result = append(result, &TemplateServerVariable{
+			Attribute:   att,
+			Placeholder: "{" + name + "}",
+			EnumCases:   strings.Join(quoted, ", "),
+			EnumList:    strings.ReplaceAll(strings.Join(variable.Enum, ", "), "\"", "\\\""),
+		})
+	}
+	return result, nil
+}
+
+// withClientOptions adds the attributes that configure the request timeout and retries, unless
+// custom attributes already configure them
+func withClientOptions(attributes []config.ProviderAttribute) []config.ProviderAttribute {
+	builtin := []config.ProviderAttribute{
+		{
+			Name:        "max_retries",
+			Type:        config.ProviderAttributeInt,
+			Description: "The maximum number of times a request is retried after it was rate limited or failed temporarily",
+			Default:     3,
+			Option:      config.MaxRetriesOption,
+		},
+		{
+			Name:        "request_timeout",
+			Description: "The maximum duration of each request, like `30s`. By default, requests do not time out",
+			Option:      config.TimeoutOption,
+		},
+	}
+
+	result := append([]config.ProviderAttribute{}, attributes...)
+	for _, option := range builtin {
+		configured := false
+		for _, att := range attributes {
+			configured = configured || att.Option == option.Option
+		}
+		if !configured {
+			result = append(result, option)
+		}
+	}
+	return result
+}
+
+// templateSettings describes the custom provider attributes declared in config
+func templateSettings(attributes []config.ProviderAttribute) ([]*TemplateProviderSetting, error) {
+	result := make([]*TemplateProviderSetting, 0, len(attributes))
+	for _, custom := range attributes {
+		if err := custom.Validate(); err != nil {
+			return nil, err
+		}
+
+		att := &TemplateProviderAttribute{
+			TfName:      custom.Name,
+			DataName:    naming.ToTitleName(custom.Name),
+			Description: custom.Description,
+			Sensitive:   custom.Sensitive,
+		}
+
+		var defaultValue string
+		switch custom.AttributeType() {
+		case config.ProviderAttributeString:
+			if value, ok := custom.Default.(string); ok {
+				att.Default = fmt.Sprintf("types.StringValue(%s)", strconv.Quote(value))
+				defaultValue = value
+			}
+		case config.ProviderAttributeBool:
+			att.Schema = SchemaBool
+			if value, ok := custom.Default.(bool); ok {
+				att.Default = fmt.Sprintf("types.BoolValue(%t)", value)
+				defaultValue = strconv.FormatBool(value)
+			}
+		case config.ProviderAttributeInt:
+			att.Schema = SchemaInt64
+			if value, ok := custom.Default.(int); ok {
+				att.Default = fmt.Sprintf("types.Int64Value(%d)", value)
+				defaultValue = strconv.Itoa(value)
+			}
+		case config.ProviderAttributeFloat:
+			att.Schema = SchemaFloat64
+			if custom.Default != nil {
+				defaultValue = fmt.Sprint(custom.Default)
+				att.Default = fmt.Sprintf("types.Float64Value(%s)", defaultValue)
+			}
+		case config.ProviderAttributeList:
+			att.Schema = SchemaList
+			if items, ok := custom.Default.([]interface{}); ok {
+				quoted := make([]string, 0, len(items))
+				values := make([]string, 0, len(items))
+				for _, item := range items {
+					quoted = append(quoted, strconv.Quote(item.(string)))
+					values = append(values, item.(string))
+				}
+				att.Default = fmt.Sprintf("types.ListValueFrom(ctx, types.StringType, []string{%s})", strings.Join(quoted, ", "))
+				defaultValue = strings.Join(values, ", ")
+			}
+		}
+
+		if len(att.Description) == 0 {
+			att.Description = fmt.Sprintf("The %s setting", custom.Name)
+		}
+		if len(att.Default) > 0 {
+			att.Description = fmt.Sprintf("%s. Defaults to `%s`.", strings.TrimSuffix(att.Description, "."), defaultValue)
+		}
+		att.Description = strings.ReplaceAll(att.Description, "\"", "\\\"")
+
+		result = append(result, &TemplateProviderSetting{
+			Attribute: att,
+			In:        restutils.In(strings.ToLower(custom.In)),
+			Parameter: custom.Parameter,
+			Option:    custom.Option,
+		})
+	}
+	return result, nil
+}
+
+// templateParameters describes the provider attributes of the parameters common to most operations
+func (g *ProviderGenerator) templateParameters() []*TemplateProviderParameter {
+	result := make([]*TemplateProviderParameter, 0, len(g.Config.Api.Parameters))
+	for _, parameter := range g.Config.Api.Parameters {
+		in := restutils.In(strings.ToLower(parameter.In))
+		description := findParameterDescription(g.Doc, in, parameter.Name)
+		if len(description) == 0 {
+			description = fmt.Sprintf("The %s parameter %s, sent with each request that accepts it", in, parameter.Name)
+		}
+
+		result = append(result, &TemplateProviderParameter{
+			Attribute: &TemplateProviderAttribute{
+				TfName:      naming.ToHCLName(parameter.Name),
+				DataName:    naming.ToTitleName(parameter.Name),
+				Description: strings.ReplaceAll(description, "\"", "\\\""),
+			},
+			Name: parameter.Name,
+			In:   in,
+		})
+	}
+	return result
+}
+
+// findParameterDescription finds the description of the first operation parameter with the
+// specified location and name
+func findParameterDescription(doc *openapi3.T, in restutils.In, name string) string {
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
 	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		for _, op := range doc.Paths[path].Operations() {
+			for _, paramRef := range op.Parameters {
+				if paramRef.Value != nil && paramRef.Value.In == string(in) && restutils.SameParameterName(in, paramRef.Value.Name, name) {
+					if len(paramRef.Value.Description) > 0 {
+						return paramRef.Value.Description
+					}
+				}
+			}
+		}
+	}
+	return ""
 }
 
 func (g *ProviderGenerator) Generate(destinationDirectory string) error {
+	var err error
+	if g.data, err = g.templateData(); err != nil {
+		// Provided error message is adequate
+		return err
+	}
+
 	return execute(g, fmt.Sprintf("%s/provider.go", destinationDirectory))
 }
 
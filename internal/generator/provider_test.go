@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"go/format"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/brandonc/tfpgen/internal/config"
+)
+
+// generateProvider renders the provider of the fixture, after its configuration is adjusted,
+// and returns the formatted source
+func generateProvider(t *testing.T, fixture string, configure func(*config.Config)) string {
+	t.Helper()
+
+	doc, cfg := testConfig(t, fixture)
+	if configure != nil {
+		configure(cfg)
+	}
+
+	dir := t.TempDir()
+	if err := NewProviderGenerator(doc, cfg).Generate(dir); err != nil {
+		t.Fatalf("could not generate provider: %s", err)
+	}
+
+	source, err := os.ReadFile(filepath.Join(dir, "provider.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	formatted, err := format.Source(source)
+	if err != nil {
+		t.Fatalf("generated provider is not valid go: %s\n\n%s", err, source)
+	}
+	return string(formatted)
+}
+
+func Test_templateSettings(t *testing.T) {
+	settings, err := templateSettings([]config.ProviderAttribute{
+		{Name: "insecure", Type: config.ProviderAttributeBool, Default: false, Option: config.InsecureSkipVerifyOption},
+		{Name: "timeout", Description: "The request timeout", Default: "30s", Option: config.TimeoutOption},
+		{Name: "ratio", Type: config.ProviderAttributeFloat, Default: 1},
+		{Name: "zones", Type: config.ProviderAttributeList, Default: []interface{}{"a", "b"}},
+		{Name: "tenant", In: "Header", Parameter: "X-Tenant"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	expected := []struct {
+		schema      FrameworkAttributeSchemaString
+		value       string
+		description string
+	}{
+		{SchemaBool, "types.BoolValue(false)", "The insecure setting. Defaults to `false`."},
+		{SchemaString, `types.StringValue("30s")`, "The request timeout. Defaults to `30s`."},
+		{SchemaFloat64, "types.Float64Value(1)", "The ratio setting. Defaults to `1`."},
+		{SchemaList, `types.ListValueFrom(ctx, types.StringType, []string{"a", "b"})`, "The zones setting. Defaults to `a, b`."},
+		{SchemaString, "", "The tenant setting"},
+	}
+
+	for i, e := range expected {
+		att := settings[i].Attribute
+		if att.SchemaAttribute() != e.schema || att.Default != e.value || att.Description != e.description {
+			t.Errorf("expected %s attribute %s, %q, %q, got %s, %q, %q",
+				att.TfName, e.schema, e.value, e.description, att.SchemaAttribute(), att.Default, att.Description)
+		}
+	}
+
+	if tenant := settings[4]; tenant.In != "header" || tenant.Parameter != "X-Tenant" {
+		t.Errorf("expected the tenant to be sent as the X-Tenant header, got %#v", tenant)
+	}
+
+	if _, err = templateSettings([]config.ProviderAttribute{{Name: "retries", Type: config.ProviderAttributeString, Option: config.MaxRetriesOption}}); err == nil {
+		t.Error("expected the max_retries option to require an int attribute")
+	}
+}
+
+func Test_ProviderSettings(t *testing.T) {
+	source := generateProvider(t, "../../test-fixtures/security.yaml", func(cfg *config.Config) {
+		cfg.Provider.Attributes = []config.ProviderAttribute{
+			{Name: "insecure", Type: config.ProviderAttributeBool, Option: config.InsecureSkipVerifyOption},
+			{Name: "timeout", Default: "30s", Option: config.TimeoutOption},
+			{Name: "retries", Type: config.ProviderAttributeInt, Option: config.MaxRetriesOption},
+			{Name: "tenant", In: "header", Parameter: "X-Tenant"},
+		}
+	})
+
+	expectSource(t, source,
+		"Insecure types.Bool `tfsdk:\"insecure\"`",
+		`"insecure": schema.BoolAttribute{`,
+		`parsed, err := strconv.ParseBool(value)`,
+		`data.Timeout = types.StringValue("30s")`,
+		`client.Settings["insecure"] = value`,
+		`client.SetInsecureSkipVerify(value)`,
+		`if err := client.SetTimeout(value); err != nil {`,
+		`client.GlobalParameters[Parameter{In: "header", Name: "X-Tenant"}] = value`,
+	)
+}
+
+func Test_ProviderEndpoint(t *testing.T) {
+	t.Run("absolute", func(t *testing.T) {
+		source := generateProvider(t, "../../test-fixtures/security.yaml", func(cfg *config.Config) {
+			cfg.Api.DefaultEndpoint = "https://api.example.com/v3"
+		})
+
+		expectSource(t, source, `data.Endpoint = types.StringValue("https://api.example.com/v3")`)
+		unexpectSource(t, source, `"Missing Endpoint"`)
+	})
+
+	t.Run("relative", func(t *testing.T) {
+		source := generateProvider(t, "../../test-fixtures/security.yaml", func(cfg *config.Config) {
+			cfg.Api.DefaultEndpoint = "/v3"
+		})
+
+		expectSource(t, source, `"Missing Endpoint"`, "the relative server URL /v3.")
+		unexpectSource(t, source, `data.Endpoint = types.StringValue("/v3")`)
+	})
+}
+
+func Test_ProviderRetries(t *testing.T) {
+	source := generateProvider(t, "../../test-fixtures/security.yaml", nil)
+
+	expectSource(t, source,
+		`"max_retries": schema.Int64Attribute{`,
+		`data.MaxRetries = types.Int64Value(3)`,
+		`client.MaxRetries = int(value)`,
+		`"request_timeout": schema.StringAttribute{`,
+	)
+}
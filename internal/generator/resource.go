@@ -4,6 +4,7 @@ package generator
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/brandonc/tfpgen/internal/config"
 	"github.com/brandonc/tfpgen/pkg/naming"
@@ -18,6 +19,7 @@ type ResourceGenerator struct {
 
 	currentResource  *restutils.RESTResource
 	currentTerraform *config.TerraformResource
+	currentData      *TemplateResourceData
 }
 
 // TemplateResourceData describes a single resource to be templated
@@ -30,6 +32,53 @@ type TemplateResourceData struct {
 	ResourceStruct               string
 	Description                  string
 	Attributes                   []*TemplateResourceAttribute
+
+	// The data field name of the attribute that identifies the resource
+	IdentityDataName string
+
+	// Whether the "id" attribute is generated from the identity attribute
+	GenerateID bool
+
+	Create *TemplateOperation
+	Read   *TemplateOperation
+	Update *TemplateOperation
+	Delete *TemplateOperation
+
+	// The default timeouts of the timeouts attribute, or nil if the resource has none
+	Timeouts *TemplateTimeouts
+
+	// The name of the generated map of attribute names keyed by API name, and its entries
+	AttributeNamesVar string
+	AttributeNames    map[string]string
+
+	// Whether the entity tag of the resource is kept in private state, so that updates and
+	// deletes are conditional on the version of the resource
+	ETag bool
+
+	// How to wait for the API to be consistent after create and update, or nil if it is not
+	// eventually consistent
+	Consistency *TemplateConsistency
+}
+
+// TemplateConsistency describes how to wait for an eventually consistent API
+type TemplateConsistency struct {
+	// The JSON names of the attributes that must match the plan
+	Attributes []string
+
+	// The Go expressions of the time between reads and the longest time to wait
+	Interval string
+	Timeout  string
+
+	// The longest time to wait, like "2m0s"
+	TimeoutDescription string
+}
+
+// TemplateTimeouts are the Go expressions of the default duration of each action
+type TemplateTimeouts struct {
+	Create string
+	Read   string
+	Update string
+	Delete string
 }
 
 var _ Generator = (*ResourceGenerator)(nil)
@@ -40,45 +89,227 @@ package {{ .PackageName }}
 
 import (
 	"context"
+	{{- if .ETag }}
+	"encoding/json"
+	{{- end }}
+	{{- if .Consistency }}
+	"errors"
+	{{- end }}
+	"fmt"
+	{{- if .Consistency }}
+	"net/http"
+	{{- end }}
+	{{- if or .UsesAsync .Timeouts .Consistency }}
+	"time"
+	{{- end }}
 
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	{{- if .Create.IdempotencyKey }}
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	{{- end }}
+	{{- if .Timeouts }}
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	{{- end }}
+	{{- if .ETag }}
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	{{- end }}
+	{{- if .UsesElementTypes }}
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	{{- end }}
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-type {{ .ResourceStruct }} struct{}
+type {{ .ResourceStruct }} struct {
+	client *Client
+}
 
 func New{{ .TerraformTypeName }}() resource.Resource {
 	return &{{ .ResourceStruct }}{}
 }
 
-{{ define "DataStruct" }}struct {
+var (
+	{{- range $op := .Operations }}
+	{{ .VarName }} = &Operation{
+		Method:    "{{ .Method }}",
+		Path:      "{{ .Path }}",
+		MediaType: "{{ .MediaType }}",
+		{{- if .ProviderParameters }}
+		Parameters: []Parameter{
+			{{- range .ProviderParameters }}
+			{In: "{{ .In }}", Name: "{{ .Name }}"},
+			{{- end }}
+		},
+		{{- end }}
+		{{- if .SecuritySpecified }}
+		Security: []SecurityRequirement{
+			{{- range .Security }}
+			{ {{- range $index, $scheme := .Schemes }}{{ if $index }}, {{ end }}"{{ .Name }}": {{ if .Scopes }}{ {{- range $i, $scope := .Scopes }}{{ if $i }}, {{ end }}"{{ $scope }}"{{ end -}} }{{ else }}nil{{ end }}{{ end -}} },
+			{{- end }}
+		},
+		{{- end }}
+		{{- if .Retry }}
+		Retry: []int{ {{- range $index, $code := .Retry }}{{ if $index }}, {{ end }}{{ $code }}{{ end -}} },
+		{{- end }}
+		{{- if .NotFound }}
+		NotFound: []int{ {{- range $index, $code := .NotFound }}{{ if $index }}, {{ end }}{{ $code }}{{ end -}} },
+		{{- end }}
+		{{- if .IdempotencyKey }}
+		IdempotencyKey: {{ printf "%q" .IdempotencyKey }},
+		{{- end }}
+		{{- if .Envelope }}
+		Envelope: {{ printf "%q" .Envelope }},
+		{{- end }}
+		{{- with .JSONAPI }}
+		JSONAPI: &JSONAPI{
+			Type: {{ printf "%q" .Type }},
+			{{- if .Relationships }}
+			Relationships: []Relationship{
+				{{- range .Relationships }}
+				{Name: {{ printf "%q" .Name }}, Attribute: {{ printf "%q" .Attribute }}, Type: {{ printf "%q" .Type }}{{ if .Many }}, Many: true{{ end }}},
+				{{- end }}
+			},
+			{{- end }}
+		},
+		{{- end }}
+		{{- with .Patch }}
+		Patch: {{ printf "%q" .Format }},
+		{{- end }}
+		{{- if .RequestMediaType }}
+		RequestMediaType: {{ printf "%q" .RequestMediaType }},
+		{{- end }}
+		{{- if .Files }}
+		Files: []string{ {{- range $index, $name := .Files }}{{ if $index }}, {{ end }}{{ printf "%q" $name }}{{ end -}} },
+		{{- end }}
+		{{- if .Pointers }}
+		Pointers: map[string]string{
+			{{- range $name, $pointer := .Pointers }}
+			{{ printf "%q" $name }}: {{ printf "%q" $pointer }},
+			{{- end }}
+		},
+		{{- end }}
+		{{- with .Errors }}
+		Errors: &ErrorSchema{
+			{{- if .Code }}
+			Code: {{ printf "%q" .Code }},
+			{{- end }}
+			Message: {{ printf "%q" .Message }},
+			{{- if .Details }}
+			Details: {{ printf "%q" .Details }},
+			DetailField: {{ printf "%q" .DetailField }},
+			DetailMessage: {{ printf "%q" .DetailMessage }},
+			{{- end }}
+		},
+		{{- end }}
+		{{- with .Async }}
+		Async: &Async{
+			Header: {{ printf "%q" .Header }},
+			{{- if .URLPointer }}
+			URLPointer: {{ printf "%q" .URLPointer }},
+			{{- end }}
+			{{- if .StatusPointer }}
+			StatusPointer: {{ printf "%q" .StatusPointer }},
+			Success: []string{ {{- range $index, $value := .Success }}{{ if $index }}, {{ end }}{{ printf "%q" $value }}{{ end -}} },
+			Failure: []string{ {{- range $index, $value := .Failure }}{{ if $index }}, {{ end }}{{ printf "%q" $value }}{{ end -}} },
+			{{- end }}
+			Interval: {{ .Interval }},
+		},
+		{{- end }}
+	}
+	{{- end }}
+
+	// The attribute names keyed by API name, which locate the attributes rejected by the API
+	{{ .AttributeNamesVar }} = map[string]string{
+		{{- range $apiName, $tfName := .AttributeNames }}
+		{{ printf "%q" $apiName }}: {{ printf "%q" $tfName }},
+		{{- end }}
+	}
+)
+
+{{ define "DataField" }}{{ .DataName }} {{ if .IsComplex }}*{{ if .IsList }}[]{{ end }}struct {
 	{{- range $attribute := .Attributes }}
-		{{ if .IsComplex }}{{ .DataName }} {{ template "DataStruct" . }}{{ else }}
-			{{ .DataName }} {{ .Schema.DataType }} ` + "`tfsdk:\"{{ .TfName }}\"`" + `
-		{{ end }}
-	{{- end}}
-} ` + "`tfsdk:\"{{ .TfName }}\"`" + `{{ end }}
+	{{ template "DataField" $attribute }}
+	{{- end }}
+}{{ else }}*{{ .Schema.DataType }}{{ end }} ` + "`tfsdk:\"{{ .TfName }}\" json:\"{{ .JSONTag }}\"`" + `{{ end }}
 
 type {{ .ResourceStruct }}Data struct {
 	{{- range $attribute := .Attributes }}
-		{{ if .IsComplex }}{{ .DataName }} {{ template "DataStruct" . }}{{ else }}
-			{{ .DataName }} {{ .Schema.DataType }} ` + "`tfsdk:\"{{ .TfName }}\"`" + `
-		{{ end }}
-	{{- end}}
+	{{ template "DataField" $attribute }}
+	{{- end }}
+	{{- if .Timeouts }}
+
+	Timeouts timeouts.Value ` + "`tfsdk:\"timeouts\" json:\"-\"`" + `
+	{{- end }}
+	{{- if .Create.IdempotencyKey }}
+
+	IdempotencyKey *string ` + "`tfsdk:\"idempotency_key\" json:\"-\"`" + `
+	{{- end }}
+	{{- if .ETag }}
+
+	// The entity tag of the version of the resource, which is kept in private state
+	etag string
+	{{- end }}
+}
+
+// syncID sets the id attribute from the attribute that identifies the resource
+func (d *{{ .ResourceStruct }}Data) syncID() {
+	{{- if .GenerateID }}
+	d.Id = stringPtr(d.{{ .IdentityDataName }})
+	{{- end }}
+}
+{{- if .ETag }}
+
+// loadETag sets the entity tag from private state
+func (d *{{ .ResourceStruct }}Data) loadETag(ctx context.Context, private interface {
+	GetKey(context.Context, string) ([]byte, diag.Diagnostics)
+}) diag.Diagnostics {
+	value, diags := private.GetKey(ctx, "etag")
+	if len(value) > 0 && !diags.HasError() {
+		if err := json.Unmarshal(value, &d.etag); err != nil {
+			diags.AddError("Invalid Private State", fmt.Sprintf("Unable to read the entity tag of {{ .TerraformTypeName }}, got error: %s", err))
+		}
+	}
+	return diags
 }
 
+// storeETag saves the entity tag in private state
+func (d *{{ .ResourceStruct }}Data) storeETag(ctx context.Context, private interface {
+	SetKey(context.Context, string, []byte) diag.Diagnostics
+}) diag.Diagnostics {
+	value, _ := json.Marshal(d.etag)
+	return private.SetKey(ctx, "etag", value)
+}
+{{- end }}
+
 func (r *{{ .ResourceStruct }}) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_{{ .TerraformTypeName }}"
 }
 
+func (r *{{ .ResourceStruct }}) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
 {{ define "SimpleAttr" }}
 	"{{.TfName}}": schema.{{.Schema.FrameworkSchemaAttributeType}}{
 		MarkdownDescription: "{{ .Description }}",
 		{{ if .Schema.ElementType }}ElementType: types.{{ .Schema.ElementType }},{{ end }}
 		Required:            {{ .Required }},
 		Optional:            {{ .Optional }},
+		Computed:            {{ .Computed }},
 		Sensitive:           {{ .Sensitive }},
 	},{{ end }}
 {{ define "ComplexListAttr" }}
@@ -86,6 +317,7 @@ func (r *{{ .ResourceStruct }}) Metadata(_ context.Context, req resource.Metadat
 		MarkdownDescription: "{{ .Description }}",
 		Required:            {{ .Required }},
 		Optional:            {{ .Optional }},
+		Computed:            {{ .Computed }},
 		Sensitive:           {{ .Sensitive }},
 		NestedObject:        schema.NestedAttributeObject{
 			Attributes:        map[string]schema.Attribute{
@@ -98,6 +330,7 @@ func (r *{{ .ResourceStruct }}) Metadata(_ context.Context, req resource.Metadat
 		MarkdownDescription: "{{ .Description }}",
 		Required:            {{ .Required }},
 		Optional:            {{ .Optional }},
+		Computed:            {{ .Computed }},
 		Sensitive:           {{ .Sensitive }},
 		Attributes:        map[string]schema.Attribute{
 			{{- range $attr := .Attributes }}{{ template "Attr" $attr }}{{- end}}
@@ -109,30 +342,284 @@ func (t *{{ .ResourceStruct }}) Schema(ctx context.Context, req resource.SchemaR
 		MarkdownDescription: "TODO",
 		Attributes: map[string]schema.Attribute{
 			{{- range $attribute := .Attributes }}{{ template "Attr" $attribute }}{{- end}}
+			{{- if .Timeouts }}
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+			{{- end }}
+			{{- if .Create.IdempotencyKey }}
+			"idempotency_key": schema.StringAttribute{
+				MarkdownDescription: "Identifies the create call, so that the API creates the resource once however often the call is retried. Generated if not set. Set it to a value that outlives a failed apply, like the result of a random_uuid resource, so that applying again does not create the resource twice.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			{{- end }}
 		},
 	}
 }
 
+{{ define "Timeout" }}
+	{{- if . }}
+
+	timeout, diags := data.Timeouts.{{ .Action }}(ctx, {{ .Default }})
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	{{- end }}
+{{- end }}
+
+{{ define "SetParameters" }}
+	{{- range $param := .Parameters }}
+	req.Set("{{ .In }}", "{{ .Name }}", {{ .Source }}.{{ .DataName }})
+	{{- end }}
+{{- end }}
+
+{{ define "PlannedDocument" }}
+	{{- if .Consistency }}
+
+	planned, err := toDocument(data)
+	if err != nil {
+		return err
+	}
+	{{- end }}
+{{- end }}
+
+{{ define "AwaitConsistency" }}
+	{{- if .Consistency }}
+
+	// The API is eventually consistent, so the resource is read until it reflects the change
+	data.syncID()
+	return r.awaitConsistency(ctx, planned, data)
+	{{- end }}
+{{- end }}
+
+// create calls the create operation, setting data from the response
+func (r *{{ .ResourceStruct }}) create(ctx context.Context, data *{{ .ResourceStruct }}Data) error {
+	req := NewRequest()
+	{{- template "SetParameters" .Create }}
+	req.Body = data
+	{{- if .Create.IdempotencyKey }}
+
+	// Unless it is configured, the key is generated once and kept in state with the resource
+	if data.IdempotencyKey == nil {
+		key, err := newIdempotencyKey()
+		if err != nil {
+			return fmt.Errorf("could not generate idempotency key: %w", err)
+		}
+		data.IdempotencyKey = &key
+	}
+	req.Set("header", {{ printf "%q" .Create.IdempotencyKey }}, data.IdempotencyKey)
+	{{- end }}
+	{{- template "PlannedDocument" . }}
+
+	{{ if and .Consistency (not .ETag) }}_, err ={{ else }}res, err :={{ end }} r.client.Do(ctx, {{ .Create.VarName }}, req, data)
+	if err != nil {
+		return err
+	}
+	{{- if .ETag }}
+	data.etag = res.Header.Get("ETag")
+	{{- end }}
+	{{- if .Consistency }}
+	{{- template "AwaitConsistency" . }}
+	{{- else }}
+
+	{{ if .ETag -}}
+	// The create response did not describe the resource or its entity tag, so it is read instead
+	{{- else -}}
+	// The create response did not describe the resource, so it is read instead
+	{{- end }}
+	if len(res.Body) == 0 || res.Accepted{{ if .ETag }} || data.etag == ""{{ end }} {
+		return r.read(ctx, data)
+	}
+
+	data.syncID()
+	return nil
+	{{- end }}
+}
+
+// read calls the read operation, setting data from the response
+func (r *{{ .ResourceStruct }}) read(ctx context.Context, data *{{ .ResourceStruct }}Data) error {
+	req := NewRequest()
+	{{- template "SetParameters" .Read }}
+
+	// The response is decoded into an empty model, so that attributes the API no longer
+	// returns are removed from state rather than keeping their prior values
+	var result {{ .ResourceStruct }}Data
+
+	{{ if .ETag }}res{{ else }}_{{ end }}, err := r.client.Do(ctx, {{ .Read.VarName }}, req, &result)
+	if err != nil {
+		return err
+	}
+	{{- if .ETag }}
+	result.etag = res.Header.Get("ETag")
+	{{- end }}
+
+	{{- if or .RetainedAttributes .Timeouts .Create.IdempotencyKey }}
+
+	// Parameters, uploaded files, timeouts and the idempotency key are not described by the
+	// response
+	{{- range .RetainedAttributes }}
+	result.{{ .DataName }} = data.{{ .DataName }}
+	{{- end }}
+	{{- if .Timeouts }}
+	result.Timeouts = data.Timeouts
+	{{- end }}
+	{{- if .Create.IdempotencyKey }}
+	result.IdempotencyKey = data.IdempotencyKey
+	{{- end }}
+	{{- end }}
+
+	*data = result
+	data.syncID()
+	return nil
+}
+
+// update calls the update operation, identifying the resource by its prior state and setting
+// data from the response
+func (r *{{ .ResourceStruct }}) update(ctx context.Context, state, data *{{ .ResourceStruct }}Data) error {
+	// Computed attributes are not known by the configuration
+	{{- range $attribute := .Attributes }}{{ if .Computed }}
+	if data.{{ .DataName }} == nil {
+		data.{{ .DataName }} = state.{{ .DataName }}
+	}
+	{{- end }}{{ end }}
+	{{- if .Create.IdempotencyKey }}
+	if data.IdempotencyKey == nil {
+		data.IdempotencyKey = state.IdempotencyKey
+	}
+	{{- end }}
+
+	req := NewRequest()
+	{{- template "SetParameters" .Update }}
+	req.Body = data
+	{{- if .Update.Patch }}
+
+	// Only the attributes that changed since the prior state are sent
+	req.Prior = state
+	{{- end }}
+	{{- if .Update.IfMatch }}
+
+	// The update fails if the resource was changed since its prior state was read
+	if state.etag != "" {
+		req.Set("header", "If-Match", state.etag)
+	}
+	{{- end }}
+
+	{{- template "PlannedDocument" . }}
+
+	{{ if and .Consistency (not .ETag) }}_, err ={{ else }}res, err :={{ end }} r.client.Do(ctx, {{ .Update.VarName }}, req, data)
+	if err != nil {
+		return err
+	}
+	{{- if .ETag }}
+	data.etag = res.Header.Get("ETag")
+	{{- end }}
+	{{- if .Consistency }}
+	{{- template "AwaitConsistency" . }}
+	{{- else }}
+
+	{{ if .ETag -}}
+	// The update response did not describe the resource or its entity tag, so it is read instead
+	{{- else -}}
+	// The update response did not describe the resource, so it is read instead
+	{{- end }}
+	if len(res.Body) == 0 || res.Accepted{{ if .ETag }} || data.etag == ""{{ end }} {
+		return r.read(ctx, data)
+	}
+
+	data.syncID()
+	return nil
+	{{- end }}
+}
+
+{{- with .Consistency }}
+
+// awaitConsistency reads the resource until it is found{{ if .Attributes }} and its attributes match the plan{{ end }}, or
+// until {{ .TimeoutDescription }} have elapsed
+func (r *{{ $.ResourceStruct }}) awaitConsistency(ctx context.Context, planned interface{}, data *{{ $.ResourceStruct }}Data) error {
+	ctx, cancel := context.WithTimeout(ctx, {{ .Timeout }})
+	defer cancel()
+
+	reason := errors.New("the resource was not read")
+	for {
+		err := r.read(ctx, data)
+		switch {
+		case ctx.Err() != nil:
+			return fmt.Errorf("the API was not consistent after {{ .TimeoutDescription }}: %w", reason)
+		case StatusCode(err) == http.StatusNotFound:
+			reason = err
+		case err != nil:
+			return err
+		default:
+			{{- if .Attributes }}
+			matches, err := matchesPlan(planned, data, []string{ {{- range $index, $name := .Attributes }}{{ if $index }}, {{ end }}{{ printf "%q" $name }}{{ end -}} })
+			if err != nil || matches {
+				return err
+			}
+			reason = errors.New("the attributes do not match the plan")
+			{{- else }}
+			return nil
+			{{- end }}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("the API was not consistent after {{ .TimeoutDescription }}: %w", reason)
+		case <-time.After({{ .Interval }}):
+		}
+	}
+}
+{{- end }}
+
+// delete calls the delete operation
+func (r *{{ .ResourceStruct }}) delete(ctx context.Context, data *{{ .ResourceStruct }}Data) error {
+	req := NewRequest()
+	{{- template "SetParameters" .Delete }}
+	{{- if .Delete.IfMatch }}
+
+	// The delete fails if the resource was changed since it was last read
+	if data.etag != "" {
+		req.Set("header", "If-Match", data.etag)
+	}
+	{{- end }}
+
+	_, err := r.client.Do(ctx, {{ .Delete.VarName }}, req, nil)
+	return err
+}
+
 func (r *{{ .ResourceStruct }}) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data {{ .ResourceStruct }}Data
 
+	// Computed values are unknown in the plan, so the configuration is used
 	diags := req.Config.Get(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	{{- template "Timeout" (.Timeout "Create") }}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// example, err := d.provider.client.CreateExample(...)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create example, got error: %s", err))
-	//     return
-	// }
+	if err := r.create(ctx, &data); err != nil {
+		addErrorDiagnostics(&resp.Diagnostics, {{ .AttributeNamesVar }}, "Unable to create {{ .TerraformTypeName }}", err)
+		return
+	}
 
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
+	{{- if .ETag }}
+	resp.Diagnostics.Append(data.storeETag(ctx, resp.Private)...)
+	{{- end }}
 
 	tflog.Info(ctx, "created a {{ .ResourceStruct }} resource")
 }
@@ -146,41 +633,64 @@ func (r *{{ .ResourceStruct }}) Read(ctx context.Context, req resource.ReadReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	{{- template "Timeout" (.Timeout "Read") }}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// example, err := d.provider.client.ReadExample(...)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read example, got error: %s", err))
-	//     return
-	// }
+	if err := r.read(ctx, &data); err != nil {
+		{{- if .Read.NotFound }}
+		// The resource was deleted outside of terraform, so it is planned to be created again
+		if IsNotFound({{ .Read.VarName }}, err) {
+			tflog.Warn(ctx, "{{ .ResourceStruct }} resource was not found, removing it from state")
+			resp.State.RemoveResource(ctx)
+			return
+		}
+{{ end }}
+		addErrorDiagnostics(&resp.Diagnostics, {{ .AttributeNamesVar }}, "Unable to read {{ .TerraformTypeName }}", err)
+		return
+	}
 
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
+	{{- if .ETag }}
+	resp.Diagnostics.Append(data.storeETag(ctx, resp.Private)...)
+	{{- end }}
 
 	tflog.Info(ctx, "read a {{ .ResourceStruct }} resource")
 }
 
 func (r *{{ .ResourceStruct }}) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data {{ .ResourceStruct }}Data
+	var data, state {{ .ResourceStruct }}Data
+
+	// Computed values are unknown in the plan, so the configuration is used
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
 
-	diags := req.Plan.Get(ctx, &data)
+	diags = req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
+	{{- if .ETag }}
+	resp.Diagnostics.Append(state.loadETag(ctx, req.Private)...)
+	{{- end }}
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	{{- template "Timeout" (.Timeout "Update") }}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// example, err := d.provider.client.UpdateExample(...)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update example, got error: %s", err))
-	//     return
-	// }
+	if err := r.update(ctx, &state, &data); err != nil {
+		{{- if .Update.IfMatch }}
+		if IsPreconditionFailed(err) {
+			resp.Diagnostics.AddError("Resource Changed Outside Terraform", "The {{ .TerraformTypeName }} was changed since it was last read, so it was not updated. Refresh its state to review the changes, then apply again.")
+			return
+		}
+{{ end }}
+		addErrorDiagnostics(&resp.Diagnostics, {{ .AttributeNamesVar }}, "Unable to update {{ .TerraformTypeName }}", err)
+		return
+	}
 
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
+	{{- if .ETag }}
+	resp.Diagnostics.Append(data.storeETag(ctx, resp.Private)...)
+	{{- end }}
 
 	tflog.Info(ctx, "updated a {{ .ResourceStruct }} resource")
 }
@@ -190,18 +700,33 @@ func (r *{{ .ResourceStruct }}) Delete(ctx context.Context, req resource.DeleteR
 
 	diags := req.State.Get(ctx, &data)
 	resp.Diagnostics.Append(diags...)
+	{{- if .ETag }}
+	resp.Diagnostics.Append(data.loadETag(ctx, req.Private)...)
+	{{- end }}
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	{{- template "Timeout" (.Timeout "Delete") }}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// example, err := d.provider.client.DeleteExample(...)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete example, got error: %s", err))
-	//     return
-	// }
+	if err := r.delete(ctx, &data); err != nil {
+		{{- if .Delete.NotFound }}
+		// The resource was already deleted outside of terraform
+		if IsNotFound({{ .Delete.VarName }}, err) {
+			resp.State.RemoveResource(ctx)
+			tflog.Info(ctx, "{{ .ResourceStruct }} resource was already deleted")
+			return
+		}
+{{ end }}
+		{{- if .Delete.IfMatch }}
+		if IsPreconditionFailed(err) {
+			resp.Diagnostics.AddError("Resource Changed Outside Terraform", "The {{ .TerraformTypeName }} was changed since it was last read, so it was not deleted. Refresh its state to review the changes, then apply again.")
+			return
+		}
+{{ end }}
+		addErrorDiagnostics(&resp.Diagnostics, {{ .AttributeNamesVar }}, "Unable to delete {{ .TerraformTypeName }}", err)
+		return
+	}
 
 	resp.State.RemoveResource(ctx)
 
@@ -210,6 +735,71 @@ func (r *{{ .ResourceStruct }}) Delete(ctx context.Context, req resource.DeleteR
 `
 }
 
+// UsesElementTypes reports whether any attribute is a list of a simple framework type
+func (d *TemplateResourceData) UsesElementTypes() bool {
+	return usesElementTypes(d.Attributes)
+}
+
+// RetainedAttributes returns the attributes whose values are kept from the prior state when
+// the resource is read
+func (d *TemplateResourceData) RetainedAttributes() []*TemplateResourceAttribute {
+	result := make([]*TemplateResourceAttribute, 0)
+	for _, att := range d.Attributes {
+		if att.Retained() {
+			result = append(result, att)
+		}
+	}
+	return result
+}
+
+func usesElementTypes(attributes []*TemplateResourceAttribute) bool {
+	for _, att := range attributes {
+		if len(att.Schema.ElementType) > 0 || usesElementTypes(att.Attributes) {
+			return true
+		}
+	}
+	return false
+}
+
+// TemplateTimeout is the default timeout of a single action
+type TemplateTimeout struct {
+	// The name of the timeouts.Value method, like "Create"
+	Action string
+
+	// The Go expression of the default duration
+	Default string
+}
+
+// Timeout describes the timeout of the action, or is nil if the resource has no timeouts
+func (d *TemplateResourceData) Timeout(action string) *TemplateTimeout {
+	if d.Timeouts == nil {
+		return nil
+	}
+
+	defaults := map[string]string{
+		"Create": d.Timeouts.Create,
+		"Read":   d.Timeouts.Read,
+		"Update": d.Timeouts.Update,
+		"Delete": d.Timeouts.Delete,
+	}
+	return &TemplateTimeout{Action: action, Default: defaults[action]}
+}
+
+// UsesAsync reports whether any operation is asynchronous
+func (d *TemplateResourceData) UsesAsync() bool {
+	for _, op := range d.Operations() {
+		if op.Async != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Operations returns each of the bound operations
+func (d *TemplateResourceData) Operations() []*TemplateOperation {
+	return []*TemplateOperation{d.Create, d.Read, d.Update, d.Delete}
+}
+
 func (g *ResourceGenerator) PackageName() string {
 	return g.Config.Provider.PackageName
 }
@@ -239,6 +829,10 @@ func (g *ResourceGenerator) Generate(destinationPath string) error {
 			g.currentResource = resource
 			g.currentTerraform = g.Config.Output[key]
 
+			if g.currentData, err = g.templateData(); err != nil {
+				return fmt.Errorf("could not generate resource %s: %w", key, err)
+			}
+
 			err = execute(g, fmt.Sprintf("%s/resource_%s.go", destinationPath, g.currentTerraform.TfTypeNameSuffix))
 			if err != nil {
 				return err
@@ -249,16 +843,119 @@ func (g *ResourceGenerator) Generate(destinationPath string) error {
 	return nil
 }
 
-func (g *ResourceGenerator) CreateTemplateData() interface{} {
-	return &TemplateResourceData{
-		PackageName:                  "provider",
+// templateData describes the current resource, binding each of its operations to attributes
+func (g *ResourceGenerator) templateData() (*TemplateResourceData, error) {
+	attributes, err := templateAttributes(g.currentResource, g.currentTerraform)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceStruct := fmt.Sprintf("Resource%s", g.currentResource.Name)
+	varPrefix := "resource" + g.currentResource.Name
+
+	result := &TemplateResourceData{
+		PackageName:                  g.PackageName(),
 		AcceptanceTestFunctionPrefix: "AccTest_",
-		Attributes:                   templateAttributes(g.currentResource, g.currentTerraform),
+		Attributes:                   attributes,
 		TerraformTypeName:            g.currentTerraform.TfTypeNameSuffix,
 		TerraformTypeNameTitle:       naming.ToTitleName(g.currentTerraform.TfTypeNameSuffix),
 		ConfigKey:                    g.currentResource.Name,
-		ResourceStruct:               fmt.Sprintf("Resource%s", g.currentResource.Name),
+		ResourceStruct:               resourceStruct,
+	}
+
+	if id := findAttribute(attributes, "", ""); id != nil && id.TfName == "id" {
+		identity := findIdentityAttribute(g.currentResource, attributes)
+		if identity == nil {
+			return nil, fmt.Errorf("could not determine the identity attribute; specify one using the identity key")
+		}
+		result.GenerateID = true
+		result.IdentityDataName = identity.DataName
 	}
+
+	mediaType := g.currentTerraform.MediaType
+	if result.Create, err = templateOperation(varPrefix+"Create", g.currentResource, g.currentResource.RESTCreate, mediaType, attributes, &g.Config.Api); err != nil {
+		return nil, err
+	}
+	if result.Read, err = templateOperation(varPrefix+"Read", g.currentResource, g.currentResource.RESTShow, mediaType, attributes, &g.Config.Api); err != nil {
+		return nil, err
+	}
+	if result.Update, err = templateOperation(varPrefix+"Update", g.currentResource, g.currentResource.RESTUpdate, mediaType, attributes, &g.Config.Api); err != nil {
+		return nil, err
+	}
+	// The resource being updated is identified by its prior state
+	for _, param := range result.Update.Parameters {
+		if param.In == restutils.InPath {
+			param.Source = "state"
+		}
+	}
+	if result.Delete, err = templateOperation(varPrefix+"Delete", g.currentResource, g.currentResource.RESTDelete, mediaType, attributes, &g.Config.Api); err != nil {
+		return nil, err
+	}
+
+	if result.Timeouts, err = templateTimeouts(g.currentTerraform.Timeouts); err != nil {
+		return nil, err
+	}
+	for _, att := range attributes {
+		if result.Timeouts != nil && att.TfName == "timeouts" {
+			return nil, fmt.Errorf("the timeouts attribute conflicts with an attribute of the same name")
+		}
+	}
+
+	result.AttributeNamesVar = varPrefix + "AttributeNames"
+	result.AttributeNames = make(map[string]string)
+	addAttributeNames(result.AttributeNames, attributes)
+
+	// Updates and deletes can only be conditional if the read response has an entity tag
+	result.ETag = g.currentResource.ProbeForETag(g.currentResource.RESTShow) && (result.Update.IfMatch || result.Delete.IfMatch)
+	if !result.ETag {
+		result.Update.IfMatch = false
+		result.Delete.IfMatch = false
+	}
+
+	result.Create.IdempotencyKey = g.currentTerraform.IdempotencyKey
+	if result.Create.IdempotencyKey == "" {
+		result.Create.IdempotencyKey = g.currentResource.ProbeForIdempotencyKey(g.currentResource.RESTCreate)
+	}
+	if strings.ContainsAny(result.Create.IdempotencyKey, " :") {
+		return nil, fmt.Errorf("invalid idempotency key header %q", result.Create.IdempotencyKey)
+	}
+	for _, att := range attributes {
+		if result.Create.IdempotencyKey != "" && att.TfName == "idempotency_key" {
+			return nil, fmt.Errorf("the idempotency_key attribute conflicts with an attribute of the same name")
+		}
+	}
+
+	if result.Consistency, err = templateConsistency(g.currentTerraform.Consistency, attributes); err != nil {
+		return nil, fmt.Errorf("invalid consistency config: %w", err)
+	}
+
+	notFound := g.currentTerraform.NotFound
+	if notFound == nil {
+		notFound = config.NewNotFoundConfig(g.currentResource)
+	}
+	if err = notFound.Validate(); err != nil {
+		return nil, err
+	}
+	result.Read.NotFound = notFound.Read
+	result.Delete.NotFound = notFound.Delete
+
+	if async := g.currentTerraform.Async; async != nil {
+		if result.Create.Async, err = templateAsync(async.CreateAction); err != nil {
+			return nil, fmt.Errorf("invalid create async config: %w", err)
+		}
+		if result.Update.Async, err = templateAsync(async.UpdateAction); err != nil {
+			return nil, fmt.Errorf("invalid update async config: %w", err)
+		}
+		if result.Delete.Async, err = templateAsync(async.DeleteAction); err != nil {
+			return nil, fmt.Errorf("invalid delete async config: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+func (g *ResourceGenerator) CreateTemplateData() interface{} {
+	return g.currentData
 }
 
 func NewResourceGenerator(doc *openapi3.T, config *config.Config) *ResourceGenerator {
@@ -267,3 +964,69 @@ func NewResourceGenerator(doc *openapi3.T, config *config.Config) *ResourceGener
 		Config: config,
 	}
 }
+
+// templateTimeouts describes the default timeout of each action, or is nil if the resource has
+// no timeouts
+func templateTimeouts(timeouts *config.TimeoutsConfig) (*TemplateTimeouts, error) {
+	if timeouts == nil {
+		return nil, nil
+	}
+
+	durations := make(map[restutils.RESTPseudonym]string)
+	for _, action := range []restutils.RESTPseudonym{restutils.Create, restutils.Show, restutils.Update, restutils.Delete} {
+		d, err := timeouts.Default(action)
+		if err != nil {
+			return nil, err
+		}
+		durations[action] = durationExpression(d)
+	}
+
+	return &TemplateTimeouts{
+		Create: durations[restutils.Create],
+		Read:   durations[restutils.Show],
+		Update: durations[restutils.Update],
+		Delete: durations[restutils.Delete],
+	}, nil
+}
+
+// addAttributeNames adds the names of the attributes and their nested attributes, keyed by API
+// name. If attributes at different levels share an API name, the outermost is kept.
+func addAttributeNames(names map[string]string, attributes []*TemplateResourceAttribute) {
+	for _, att := range attributes {
+		if _, ok := names[att.ApiName]; !ok && len(att.ApiName) > 0 {
+			names[att.ApiName] = att.TfName
+		}
+	}
+	for _, att := range attributes {
+		addAttributeNames(names, att.Attributes)
+	}
+}
+
+// templateConsistency describes how to wait for an eventually consistent API. The result is nil
+// if the API is not eventually consistent.
+func templateConsistency(consistency *config.ConsistencyConfig, attributes []*TemplateResourceAttribute) (*TemplateConsistency, error) {
+	if consistency == nil {
+		return nil, nil
+	}
+
+	interval, timeout, err := consistency.Durations()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TemplateConsistency{
+		Attributes:         make([]string, 0, len(consistency.Attributes)),
+		Interval:           durationExpression(interval),
+		Timeout:            durationExpression(timeout),
+		TimeoutDescription: timeout.String(),
+	}
+
+	for _, name := range consistency.Attributes {
+		att := findAttribute(attributes, name, restutils.InContent)
+		if att == nil {
+			return nil, fmt.Errorf("%s is not an attribute of the request body", name)
+		}
+		result.Attributes = append(result.Attributes, att.ApiName)
+	}
+	return result, nil
+}
@@ -0,0 +1,398 @@
+package generator
+
+import (
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brandonc/tfpgen/internal/config"
+	"github.com/brandonc/tfpgen/pkg/restutils"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// testConfig loads a fixture and initializes the configuration of its resources the way the
+// init command does
+func testConfig(t *testing.T, fixture string) (*openapi3.T, *config.Config) {
+	t.Helper()
+
+	doc, err := openapi3.NewLoader().LoadFromFile(fixture)
+	if err != nil {
+		t.Fatalf("invalid fixture: %s", err)
+	}
+
+	cfg := &config.Config{
+		Provider: config.ProviderConfig{
+			Name:             "yourname/example",
+			ModuleRepository: "github.com/yourname/terraform-provider-example",
+			PackageName:      "provider",
+		},
+		Filename: fixture,
+		Output:   make(map[string]*config.TerraformResource),
+	}
+
+	probe := restutils.NewProbe(doc)
+	for name, resource := range probe.ProbeForResources() {
+		if tfResource := config.NewTerraformResource(resource); tfResource != nil {
+			cfg.Output[name] = tfResource
+		}
+	}
+	return doc, cfg
+}
+
+// generateResource renders a resource of the fixture, after its configuration is adjusted,
+// and returns the formatted source
+func generateResource(t *testing.T, fixture, name string, configure func(*config.Config)) string {
+	t.Helper()
+
+	doc, cfg := testConfig(t, fixture)
+	if configure != nil {
+		configure(cfg)
+	}
+
+	resource, ok := cfg.Output[name]
+	if !ok {
+		t.Fatalf("expected %q resource", name)
+	}
+
+	dir := t.TempDir()
+	if err := NewResourceGenerator(doc, cfg).Generate(dir); err != nil {
+		t.Fatalf("could not generate resources: %s", err)
+	}
+
+	source, err := os.ReadFile(filepath.Join(dir, "resource_"+resource.TfTypeNameSuffix+".go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	formatted, err := format.Source(source)
+	if err != nil {
+		t.Fatalf("generated resource is not valid go: %s\n\n%s", err, source)
+	}
+	return string(formatted)
+}
+
+// providerTests has the tests shared by the generated providers, and a directory of tests for
+// each fixture
+const providerTests = "testdata/provider"
+
+// buildProviderTests generates the provider of the fixture, after its configuration is
+// adjusted, into a module with the shared provider tests and the tests in dir, and returns the
+// path of the compiled test binary and the names of the tests
+func buildProviderTests(t *testing.T, fixture, dir string, configure func(*config.Config)) (string, []string) {
+	t.Helper()
+
+	doc, cfg := testConfig(t, fixture)
+	if configure != nil {
+		configure(cfg)
+	}
+
+	module := t.TempDir()
+	if err := os.Mkdir(filepath.Join(module, "provider"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateAll(module, doc, cfg); err != nil {
+		t.Fatalf("could not generate provider: %s", err)
+	}
+
+	tests, err := filepath.Glob(filepath.Join(providerTests, dir, "*_test.go"))
+	if err != nil || len(tests) == 0 {
+		t.Fatalf("expected tests in %s", dir)
+	}
+	for _, path := range append(tests, filepath.Join(providerTests, "provider_test.go")) {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(filepath.Join(module, "provider", filepath.Base(path)), source, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	binary := filepath.Join(module, "provider.test")
+	for _, args := range [][]string{{"mod", "tidy"}, {"test", "-c", "-o", binary, "./provider"}} {
+		cmd := exec.Command("go", args...)
+		cmd.Dir = module
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("could not build provider tests: go %s: %s\n\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+	return binary, testNames(t, tests...)
+}
+
+// runProviderTests runs the tests in dir against the generated provider of the fixture
+func runProviderTests(t *testing.T, fixture, dir string, configure func(*config.Config)) {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("builds the generated provider")
+	}
+
+	binary, names := buildProviderTests(t, fixture, dir, configure)
+	runTests(t, binary, names)
+}
+
+// operationSource returns the declaration of the generated Operation variable
+func operationSource(t *testing.T, source, varName string) string {
+	t.Helper()
+
+	start := strings.Index(source, "\t"+varName+" = &Operation{")
+	if start < 0 {
+		t.Fatalf("expected generated operation %s", varName)
+	}
+	end := strings.Index(source[start:], "\n\t}\n")
+	return source[start : start+end+3]
+}
+
+// normalizeSource collapses whitespace, so that expectations don't depend on alignment
+func normalizeSource(source string) string {
+	return strings.Join(strings.Fields(source), " ")
+}
+
+// expectSource fails if the generated source does not contain each of the expected fragments
+func expectSource(t *testing.T, source string, expected ...string) {
+	t.Helper()
+
+	for _, fragment := range expected {
+		if !strings.Contains(normalizeSource(source), normalizeSource(fragment)) {
+			t.Errorf("expected generated source to contain %q, got:\n\n%s", fragment, source)
+		}
+	}
+}
+
+// unexpectSource fails if the generated source contains any of the fragments
+func unexpectSource(t *testing.T, source string, unexpected ...string) {
+	t.Helper()
+
+	for _, fragment := range unexpected {
+		if strings.Contains(normalizeSource(source), normalizeSource(fragment)) {
+			t.Errorf("expected generated source not to contain %q", fragment)
+		}
+	}
+}
+
+func Test_ResourceSecurity(t *testing.T) {
+	t.Run("configured schemes", func(t *testing.T) {
+		source := generateResource(t, "../../test-fixtures/security.yaml", "Widgets", func(cfg *config.Config) {
+			cfg.Api.SecuritySchemes = map[string]*config.SecuritySchemeConfig{
+				"BearerAuth": {Type: config.TokenSecurityScheme},
+				"Basic":      {Type: config.BasicSecurityScheme},
+				"ApiKey":     {Type: config.ApiKeySecurityScheme, Name: "api_key", In: "query"},
+				"OAuth2":     {Type: config.ClientCredentialsSecurityScheme, TokenURL: "https://auth.example.com/oauth/token"},
+			}
+		})
+
+		expectSource(t, operationSource(t, source, "resourceWidgetsCreate"), `Security: []SecurityRequirement{
+			{"OAuth2": {"widgets:write"}},
+			{"ApiKey": nil},
+		},`)
+		expectSource(t, operationSource(t, source, "resourceWidgetsRead"), `Security: []SecurityRequirement{
+			{"BearerAuth": nil},
+		},`)
+		expectSource(t, operationSource(t, source, "resourceWidgetsUpdate"), `Security: []SecurityRequirement{
+			{"OAuth2": {"widgets:write"}},
+		},`)
+		expectSource(t, operationSource(t, source, "resourceWidgetsDelete"), `Security: []SecurityRequirement{
+			{"ApiKey": nil, "Basic": nil},
+		},`)
+	})
+
+	t.Run("single scheme", func(t *testing.T) {
+		source := generateResource(t, "../../test-fixtures/security.yaml", "Widgets", nil)
+
+		// Without named schemes, every configured credential is sent
+		unexpectSource(t, source, "Security:")
+	})
+}
+
+func Test_ResourceRetry(t *testing.T) {
+	t.Run("documented status codes", func(t *testing.T) {
+		source := generateResource(t, "../../test-fixtures/security.yaml", "Widgets", nil)
+
+		expectSource(t, operationSource(t, source, "resourceWidgetsRead"), "Retry: []int{502, 503, 504},")
+		expectSource(t, operationSource(t, source, "resourceWidgetsUpdate"), "Retry: []int{429},")
+		unexpectSource(t, operationSource(t, source, "resourceWidgetsCreate"), "Retry:")
+	})
+
+	t.Run("configured status codes", func(t *testing.T) {
+		source := generateResource(t, "../../test-fixtures/security.yaml", "Widgets", func(cfg *config.Config) {
+			cfg.Api.RetryStatusCodes = []int{429, 503}
+		})
+
+		// Only operations that document no transient status codes retry the configured ones
+		expectSource(t, operationSource(t, source, "resourceWidgetsCreate"), "Retry: []int{429, 503},")
+		expectSource(t, operationSource(t, source, "resourceWidgetsUpdate"), "Retry: []int{429},")
+	})
+}
+
+func Test_ResourceAsync(t *testing.T) {
+	runProviderTests(t, "../../test-fixtures/async.yaml", "async", func(cfg *config.Config) {
+		async := cfg.Output["Clusters"].Async
+		async.CreateAction.Interval = "10ms"
+		async.DeleteAction.Interval = "10ms"
+		async.UpdateAction = &config.AsyncConfig{
+			StatusPointer: "/status",
+			Success:       []string{"succeeded"},
+			Failure:       []string{"failed"},
+			Interval:      "10ms",
+		}
+	})
+}
+
+func Test_templateAsync(t *testing.T) {
+	if async, err := templateAsync(nil); async != nil || err != nil {
+		t.Errorf("expected synchronous actions to have no async, got %#v and error %v", async, err)
+	}
+
+	if _, err := templateAsync(&config.AsyncConfig{Interval: "soon"}); err == nil {
+		t.Error("expected an invalid interval to be an error")
+	}
+
+	if _, err := templateAsync(&config.AsyncConfig{Success: []string{"done"}}); err == nil {
+		t.Error("expected success values without a status pointer to be an error")
+	}
+
+	cases := map[time.Duration]string{
+		2 * time.Minute:        "2 * time.Minute",
+		90 * time.Second:       "90 * time.Second",
+		250 * time.Millisecond: "250 * time.Millisecond",
+	}
+	for duration, expected := range cases {
+		if actual := durationExpression(duration); actual != expected {
+			t.Errorf("expected %s to be %q, got %q", duration, expected, actual)
+		}
+	}
+}
+
+func Test_ResourceNotFound(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		runProviderTests(t, "../../test-fixtures/patch.yaml", "notfound/default", nil)
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		runProviderTests(t, "../../test-fixtures/security.yaml", "notfound/configured", func(cfg *config.Config) {
+			cfg.Output["Widgets"].NotFound = &config.NotFoundConfig{Read: []int{410}}
+		})
+	})
+}
+
+func Test_ResourceErrors(t *testing.T) {
+	runProviderTests(t, "../../test-fixtures/security.yaml", "errors", nil)
+}
+
+func Test_addAttributeNames(t *testing.T) {
+	attributes := []*TemplateResourceAttribute{
+		{ApiName: "createIndex", TfName: "create_index"},
+		{ApiName: "limits", TfName: "limits", Attributes: []*TemplateResourceAttribute{
+			{ApiName: "regionLimit", TfName: "region_limit"},
+			{ApiName: "createIndex", TfName: "nested_create_index"},
+		}},
+		{TfName: "id"},
+	}
+
+	names := make(map[string]string)
+	addAttributeNames(names, attributes)
+
+	expected := map[string]string{
+		"createIndex": "create_index",
+		"limits":      "limits",
+		"regionLimit": "region_limit",
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected top level names to take precedence, got %v", names)
+	}
+}
+
+func Test_ResourcePatch(t *testing.T) {
+	t.Run("partial", func(t *testing.T) {
+		runProviderTests(t, "../../test-fixtures/patch.yaml", "patch/partial", nil)
+	})
+
+	t.Run("replacement", func(t *testing.T) {
+		runProviderTests(t, "../../test-fixtures/security.yaml", "patch/replacement", nil)
+	})
+}
+
+func Test_ResourceETag(t *testing.T) {
+	runProviderTests(t, "../../test-fixtures/patch.yaml", "etag", nil)
+}
+
+func Test_ResourceIdempotencyKey(t *testing.T) {
+	t.Run("documented header", func(t *testing.T) {
+		runProviderTests(t, "../../test-fixtures/patch.yaml", "idempotency", nil)
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		doc, cfg := testConfig(t, "../../test-fixtures/security.yaml")
+		cfg.Output["Widgets"].IdempotencyKey = "Request Id"
+
+		if err := NewResourceGenerator(doc, cfg).Generate(t.TempDir()); err == nil {
+			t.Error("expected a header name with a space to be invalid")
+		}
+	})
+
+	t.Run("conflicting attribute", func(t *testing.T) {
+		doc, cfg := testConfig(t, "../../test-fixtures/patch.yaml")
+		doc.Components.Schemas["Note"].Value.Properties["idempotency_key"] = openapi3.NewStringSchema().NewRef()
+
+		if err := NewResourceGenerator(doc, cfg).Generate(t.TempDir()); err == nil {
+			t.Error("expected an idempotency_key attribute to conflict with the generated one")
+		}
+	})
+}
+
+func Test_ResourceConsistency(t *testing.T) {
+	t.Run("configured", func(t *testing.T) {
+		runProviderTests(t, "../../test-fixtures/security.yaml", "consistency", func(cfg *config.Config) {
+			cfg.Output["Widgets"].Consistency = &config.ConsistencyConfig{Attributes: []string{"name"}, Interval: "10ms", Timeout: "500ms"}
+		})
+	})
+
+	t.Run("unknown attribute", func(t *testing.T) {
+		attributes := []*TemplateResourceAttribute{{ApiName: "name", In: restutils.InContent}}
+		if _, err := templateConsistency(&config.ConsistencyConfig{Attributes: []string{"size"}}, attributes); err == nil {
+			t.Error("expected an attribute that is not in the request body to be invalid")
+		}
+	})
+}
+
+func Test_ResourceEnvelope(t *testing.T) {
+	t.Run("probed", func(t *testing.T) {
+		runProviderTests(t, "../../test-fixtures/envelope.yaml", "envelope", nil)
+	})
+
+	t.Run("api envelope", func(t *testing.T) {
+		runProviderTests(t, "../../test-fixtures/envelope.yaml", "envelope", func(cfg *config.Config) {
+			cfg.Api.Envelope = "/data"
+			cfg.Output["Projects"].Envelope = ""
+		})
+	})
+
+	t.Run("resource envelope", func(t *testing.T) {
+		runProviderTests(t, "../../test-fixtures/envelope.yaml", "envelope", func(cfg *config.Config) {
+			cfg.Api.Envelope = "/item"
+			cfg.Output["Projects"].Envelope = "/data"
+		})
+	})
+}
+
+func Test_ResourceJSONAPI(t *testing.T) {
+	runProviderTests(t, "../../test-fixtures/jsonapi.yaml", "jsonapi", nil)
+}
+
+func Test_ResourceForms(t *testing.T) {
+	runProviderTests(t, "../../test-fixtures/forms.yaml", "forms", nil)
+}
+
+func Test_ResourceAttributeMapping(t *testing.T) {
+	runProviderTests(t, "../../test-fixtures/mapping.yaml", "mapping", func(cfg *config.Config) {
+		cfg.Output["Deployments"].Attributes = []config.AttributeConfig{
+			{Name: "labels", Pointer: "/spec/template/metadata/labels"},
+			{Name: "replicas", Pointer: "/spec/replicas"},
+		}
+	})
+}
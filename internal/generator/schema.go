@@ -1,6 +1,8 @@
 package generator
 
 import (
+	"strings"
+
 	"github.com/brandonc/tfpgen/internal/config"
 	"github.com/brandonc/tfpgen/pkg/naming"
 	"github.com/brandonc/tfpgen/pkg/restutils"
@@ -61,6 +63,16 @@ type TemplateResourceAttribute struct {
 	// The Terraform name to use for the attribute. See https://www.terraform.io/language/syntax/configuration#identifiers
 	TfName string
 
+	// The OpenAPI name of the property or parameter. Empty if the attribute is generated.
+	ApiName string
+
+	// The JSON pointer to the nested property the attribute is mapped to, or empty
+	Pointer string
+
+	// Where the attribute value is sent in requests: a path, query, header, or cookie
+	// parameter, or the content body
+	In restutils.In
+
 	// The OpenAPI description of the property
 	Description string
 
@@ -80,6 +92,15 @@ type TemplateResourceAttribute struct {
 	// If it's not required or computed, the attribute should be optional
 	Optional bool
 
+	// Whether or not the attribute is set by the API rather than the practitioner
+	Computed bool
+
+	// Whether or not the attribute identifies the resource
+	Identity bool
+
+	// Whether or not the attribute is the path of a file to upload
+	Upload bool
+
 	// Nested attributes that belong to this attribute
 	Attributes []*TemplateResourceAttribute
 
@@ -90,6 +111,21 @@ type TemplateResourceAttribute struct {
 	IsComplex bool
 }
 
+// JSONTag is the encoding/json struct tag value of the attribute's data field. Only
+// content body attributes are encoded.
+func (a *TemplateResourceAttribute) JSONTag() string {
+	if a.In != restutils.InContent {
+		return "-"
+	}
+	return a.ApiName + ",omitempty"
+}
+
+// Retained reports whether the attribute's value is kept from the prior state when the
+// resource is read. Parameters and uploaded files are not described by responses.
+func (a *TemplateResourceAttribute) Retained() bool {
+	return a.ApiName != "" && (a.In != restutils.InContent || a.Upload)
+}
+
 func typeOfSimple(t restutils.OASType, f restutils.OASFormat) TemplateResourceAttributeSchema {
 	return TemplateResourceAttributeSchema{
 		DataType:                     toSimpleGoType(t, f),
@@ -153,12 +189,22 @@ func toSimpleGoType(t restutils.OASType, f restutils.OASFormat) string {
 	}
 }
 
+// templateAttribute describes a probed attribute. Read-only attributes are computed rather than
+// optional: the API ignores configured values, and the framework rejects an apply that sets an
+// attribute the configuration left null unless it is computed. For the same reason, the
+// identity attribute is always computed unless it is required, since the API assigns it when
+// it is not configured.
 func templateAttribute(nestingLevel int, att *restutils.Attribute) *TemplateResourceAttribute {
 	result := TemplateResourceAttribute{
 		TfName:       naming.ToHCLName(att.Name),
+		ApiName:      att.Name,
+		Pointer:      att.Pointer,
+		In:           att.In,
 		Description:  att.Description,
 		Required:     att.Required,
-		Optional:     !att.Required,
+		Optional:     !att.Required && !att.ReadOnly,
+		Computed:     att.ReadOnly || (att.Identity && !att.Required),
+		Identity:     att.Identity,
 		DataName:     naming.ToTitleName(att.Name),
 		NestingLevel: nestingLevel,
 	}
@@ -186,16 +232,53 @@ func templateAttribute(nestingLevel int, att *restutils.Attribute) *TemplateReso
 
 	result.Sensitive = att.Format == "password"
 
+	// Binary properties are uploaded from files, so the attribute is the path of the file
+	if att.Format == restutils.FormatBinary && att.Type == restutils.TypeString {
+		result.Description = strings.TrimSpace("The path of the file to upload. " + att.Description)
+		result.Upload = true
+	}
+
 	return &result
 }
 
-func templateAttributes(sresource *restutils.RESTResource, tresource *config.TerraformResource) []*TemplateResourceAttribute {
-	attributes := sresource.ProbeForAttributes(tresource.MediaType)
-	result := make([]*TemplateResourceAttribute, 0, len(attributes))
+// identityAttribute is the computed "id" attribute that is generated for resources that don't
+// have one. Its value is the value of the resource identity attribute.
+func identityAttribute() *TemplateResourceAttribute {
+	return &TemplateResourceAttribute{
+		TfName:      "id",
+		Description: "The identity of the resource",
+		DataName:    "Id",
+		Computed:    true,
+		Schema:      typeOfSimple(restutils.TypeString, restutils.FormatNone),
+	}
+}
+
+func templateAttributes(sresource *restutils.RESTResource, tresource *config.TerraformResource) ([]*TemplateResourceAttribute, error) {
+	sresource.ProbeForIdentity(tresource.MediaType)
+
+	mappings, err := tresource.AttributeMappings()
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := restutils.MapAttributes(sresource.ProbeForAttributes(tresource.MediaType), mappings)
+	if err != nil {
+		return nil, err
+	}
 
+	result := make([]*TemplateResourceAttribute, 0, len(attributes)+1)
+
+	hasID := false
 	for _, att := range attributes {
-		result = append(result, templateAttribute(0, att))
+		templateAtt := templateAttribute(0, att)
+		hasID = hasID || templateAtt.TfName == "id"
+		result = append(result, templateAtt)
+	}
+
+	// The framework test harness expects every resource to have an "id" attribute
+	if !hasID {
+		result = append(result, identityAttribute())
 	}
 
-	return result
+	return result, nil
 }
@@ -0,0 +1,133 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/brandonc/tfpgen/internal/config"
+	"github.com/brandonc/tfpgen/pkg/restutils"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func Test_templateAttribute(t *testing.T) {
+	elemType := restutils.TypeString
+	cases := map[string]struct {
+		attribute                    *restutils.Attribute
+		required, optional, computed bool
+	}{
+		"required": {
+			attribute: &restutils.Attribute{Name: "name", Type: restutils.TypeString, Required: true},
+			required:  true,
+		},
+		"writable": {
+			attribute: &restutils.Attribute{Name: "description", Type: restutils.TypeString},
+			optional:  true,
+		},
+		"read-only": {
+			attribute: &restutils.Attribute{Name: "created_at", Type: restutils.TypeString, ReadOnly: true},
+			computed:  true,
+		},
+		"read-only list": {
+			attribute: &restutils.Attribute{Name: "tags", Type: restutils.TypeArray, ElemType: &elemType, ReadOnly: true},
+			computed:  true,
+		},
+		"identity": {
+			attribute: &restutils.Attribute{Name: "name", Type: restutils.TypeString, Identity: true},
+			optional:  true,
+			computed:  true,
+		},
+		"required identity": {
+			attribute: &restutils.Attribute{Name: "name", Type: restutils.TypeString, Identity: true, Required: true},
+			required:  true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual := templateAttribute(0, c.attribute)
+			if actual.Required != c.required || actual.Optional != c.optional || actual.Computed != c.computed {
+				t.Errorf("expected required %t, optional %t, computed %t, got %t, %t, %t",
+					c.required, c.optional, c.computed, actual.Required, actual.Optional, actual.Computed)
+			}
+		})
+	}
+
+	nested := templateAttribute(0, &restutils.Attribute{
+		Name: "status",
+		Type: restutils.TypeObject,
+		Attributes: []*restutils.Attribute{
+			{Name: "state", Type: restutils.TypeString, ReadOnly: true},
+		},
+	})
+	if state := nested.Attributes[0]; !state.Computed || state.Optional {
+		t.Errorf("expected nested read-only attributes to be computed, got %#v", state)
+	}
+}
+
+func Test_templateAttributesIdentity(t *testing.T) {
+	cases := map[string]string{
+		"async.yaml":    "Clusters",
+		"envelope.yaml": "Projects",
+		"jsonapi.yaml":  "Articles",
+		"mapping.yaml":  "Deployments",
+		"patch.yaml":    "Notes",
+		"security.yaml": "Widgets",
+	}
+
+	for fixture, name := range cases {
+		t.Run(fixture, func(t *testing.T) {
+			doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/" + fixture)
+			if err != nil {
+				t.Fatalf("invalid fixture: %s", err)
+			}
+
+			probe := restutils.NewProbe(doc)
+			resource, ok := probe.ProbeForResources()[name]
+			if !ok {
+				t.Fatalf("expected %q resource", name)
+			}
+
+			attributes, err := templateAttributes(resource, config.NewTerraformResource(resource))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The id is declared read-only, so the API assigns it
+			for _, att := range attributes {
+				if att.TfName == "id" && (!att.Identity || !att.Computed || att.Optional || att.Required) {
+					t.Errorf("expected the id to be a computed identity, got %#v", att)
+				}
+			}
+		})
+	}
+}
+
+func Test_Retained(t *testing.T) {
+	cases := map[string]struct {
+		attribute *restutils.Attribute
+		retained  bool
+	}{
+		"content": {
+			attribute: &restutils.Attribute{Name: "name", Type: restutils.TypeString, In: restutils.InContent},
+		},
+		"parameter": {
+			attribute: &restutils.Attribute{Name: "project_id", Type: restutils.TypeString, In: restutils.InPath},
+			retained:  true,
+		},
+		"uploaded file": {
+			attribute: &restutils.Attribute{Name: "file", Type: restutils.TypeString, Format: restutils.FormatBinary, In: restutils.InContent},
+			retained:  true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if actual := templateAttribute(0, c.attribute).Retained(); actual != c.retained {
+				t.Errorf("expected retained %t, got %t", c.retained, actual)
+			}
+		})
+	}
+
+	if identityAttribute().Retained() {
+		t.Error("expected the generated id attribute to be synced rather than retained")
+	}
+}
@@ -0,0 +1,114 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/brandonc/tfpgen/internal/config"
+	"github.com/brandonc/tfpgen/pkg/naming"
+)
+
+// TemplateSecurityScheme describes a configured security scheme and the provider attributes
+// that supply its credentials. Only the attributes used by the scheme type are set.
+type TemplateSecurityScheme struct {
+	// The name of the security scheme
+	Key string
+
+	Type config.SecurityScheme
+
+	// The api_key parameter name and where it is sent
+	Name string
+	In   string
+
+	// The oauth2_client_credentials token endpoint and default scopes
+	TokenURL string
+	Scopes   []string
+
+	Token        *TemplateProviderAttribute
+	ApiKey       *TemplateProviderAttribute
+	Username     *TemplateProviderAttribute
+	Password     *TemplateProviderAttribute
+	ClientID     *TemplateProviderAttribute
+	ClientSecret *TemplateProviderAttribute
+	ClientScopes *TemplateProviderAttribute
+}
+
+// Attributes returns the provider attributes that supply the scheme credentials
+func (s *TemplateSecurityScheme) Attributes() []*TemplateProviderAttribute {
+	result := make([]*TemplateProviderAttribute, 0, 2)
+	for _, att := range []*TemplateProviderAttribute{s.Token, s.ApiKey, s.Username, s.Password, s.ClientID, s.ClientSecret, s.ClientScopes} {
+		if att != nil {
+			result = append(result, att)
+		}
+	}
+	return result
+}
+
+// credentialAttribute describes a provider attribute that supplies a credential. When more than
+// one scheme is configured, the name is prefixed with the scheme name to tell them apart.
+func credentialAttribute(prefix, name, description string, sensitive bool) *TemplateProviderAttribute {
+	return &TemplateProviderAttribute{
+		TfName:      prefix + name,
+		DataName:    naming.ToTitleName(prefix + name),
+		Description: description,
+		Sensitive:   sensitive,
+	}
+}
+
+func scopesDescription(scopes []string) string {
+	if len(scopes) == 0 {
+		return "The OAuth2 scopes to request with the access token"
+	}
+	return fmt.Sprintf("The OAuth2 scopes to request with the access token, by default %s", strings.Join(scopes, ", "))
+}
+
+// templateSecuritySchemes describes each configured security scheme, ordered by name
+func templateSecuritySchemes(api *config.ApiConfig) ([]*TemplateSecurityScheme, error) {
+	schemes, err := api.Security()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(schemes))
+	for key := range schemes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]*TemplateSecurityScheme, 0, len(schemes))
+	for _, key := range keys {
+		scheme := schemes[key]
+		prefix := ""
+		if len(schemes) > 1 {
+			prefix = naming.ToHCLName(key) + "_"
+		}
+
+		templateScheme := &TemplateSecurityScheme{
+			Key:      key,
+			Type:     scheme.Type,
+			Name:     scheme.Name,
+			In:       scheme.In,
+			TokenURL: scheme.TokenURL,
+			Scopes:   scheme.Scopes,
+		}
+
+		switch scheme.Type {
+		case config.TokenSecurityScheme:
+			templateScheme.Token = credentialAttribute(prefix, "api_token", "The HTTP API token, sent as Authorization: Bearer header", true)
+		case config.ApiKeySecurityScheme:
+			templateScheme.ApiKey = credentialAttribute(prefix, "api_key", fmt.Sprintf("The HTTP API key, sent as the %s %s parameter", scheme.Name, scheme.In), true)
+		case config.BasicSecurityScheme:
+			templateScheme.Username = credentialAttribute(prefix, "username", "The username for HTTP basic authentication", false)
+			templateScheme.Password = credentialAttribute(prefix, "password", "The password for HTTP basic authentication", true)
+		case config.ClientCredentialsSecurityScheme:
+			templateScheme.ClientID = credentialAttribute(prefix, "client_id", "The OAuth2 client ID, exchanged for an access token", false)
+			templateScheme.ClientSecret = credentialAttribute(prefix, "client_secret", "The OAuth2 client secret, exchanged for an access token", true)
+			templateScheme.ClientScopes = credentialAttribute(prefix, "scopes", scopesDescription(scheme.Scopes), false)
+			templateScheme.ClientScopes.Schema = SchemaList
+		}
+
+		result = append(result, templateScheme)
+	}
+	return result, nil
+}
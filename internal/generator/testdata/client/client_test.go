@@ -0,0 +1,1135 @@
+// Tests of the generated HTTP API client. Test_ClientTemplate renders client.go into a module
+// alongside this file, which is not built with the generator package.
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tokenServer is a stand-in for an OAuth2 token endpoint that issues numbered tokens
+type tokenServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	issued int
+	scope  string
+}
+
+func newTokenServer(t *testing.T) *tokenServer {
+	t.Helper()
+
+	result := &tokenServer{}
+	result.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("invalid token request: %s", err)
+		}
+
+		if r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("client_id") != "id" || r.PostForm.Get("client_secret") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		result.mu.Lock()
+		result.issued++
+		result.scope = r.PostForm.Get("scope")
+		issued := result.issued
+		result.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token%d","token_type":"bearer","expires_in":3600}`, issued)
+	}))
+	t.Cleanup(result.Close)
+
+	return result
+}
+
+func (s *tokenServer) Issued() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.issued
+}
+
+// newAPIServer is a stand-in for the API that only accepts the specified token
+func newAPIServer(t *testing.T, accepted *string) *httptest.Server {
+	t.Helper()
+
+	result := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+*accepted {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"name":"example"}`)
+	}))
+	t.Cleanup(result.Close)
+
+	return result
+}
+
+func TestClientCredentialsAuth(t *testing.T) {
+	op := &Operation{
+		Method:    http.MethodGet,
+		Path:      "/example",
+		MediaType: "application/json",
+	}
+
+	setup := func(t *testing.T) (*Client, *ClientCredentialsAuth, *tokenServer, *string) {
+		tokens := newTokenServer(t)
+		accepted := "token1"
+		client := NewClient(newAPIServer(t, &accepted).URL)
+		auth := &ClientCredentialsAuth{
+			TokenURL:     tokens.URL,
+			ClientID:     "id",
+			ClientSecret: "secret",
+			Scopes:       []string{"read", "write"},
+		}
+		client.Auth["oauth2"] = auth
+		return client, auth, tokens, &accepted
+	}
+
+	t.Run("fetches and caches a token", func(t *testing.T) {
+		client, _, tokens, _ := setup(t)
+
+		for i := 0; i < 2; i++ {
+			var result struct {
+				Name string `json:"name"`
+			}
+			if _, err := client.Do(context.Background(), op, NewRequest(), &result); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+			if result.Name != "example" {
+				t.Errorf("expected the response to be decoded, got %q", result.Name)
+			}
+		}
+
+		if issued := tokens.Issued(); issued != 1 {
+			t.Errorf("expected 1 token to be issued, got %d", issued)
+		}
+
+		if tokens.scope != "read write" {
+			t.Errorf("expected scopes \"read write\", got %q", tokens.scope)
+		}
+	})
+
+	t.Run("refreshes an expired token", func(t *testing.T) {
+		client, auth, tokens, accepted := setup(t)
+
+		if _, err := client.Do(context.Background(), op, NewRequest(), nil); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		auth.mu.Lock()
+		auth.expiry = time.Now().Add(-time.Minute)
+		auth.mu.Unlock()
+		*accepted = "token2"
+
+		if _, err := client.Do(context.Background(), op, NewRequest(), nil); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if issued := tokens.Issued(); issued != 2 {
+			t.Errorf("expected 2 tokens to be issued, got %d", issued)
+		}
+	})
+
+	t.Run("retries once when the token is rejected", func(t *testing.T) {
+		client, _, tokens, accepted := setup(t)
+
+		if _, err := client.Do(context.Background(), op, NewRequest(), nil); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		// The token is revoked before it expires
+		*accepted = "token2"
+
+		if _, err := client.Do(context.Background(), op, NewRequest(), nil); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if issued := tokens.Issued(); issued != 2 {
+			t.Errorf("expected 2 tokens to be issued, got %d", issued)
+		}
+	})
+
+	t.Run("does not retry more than once", func(t *testing.T) {
+		client, _, tokens, accepted := setup(t)
+		*accepted = "never"
+
+		_, err := client.Do(context.Background(), op, NewRequest(), nil)
+		apiErr, ok := err.(*APIError)
+		if !ok || apiErr.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected an unauthorized error, got %v", err)
+		}
+
+		if issued := tokens.Issued(); issued != 2 {
+			t.Errorf("expected 2 tokens to be issued, got %d", issued)
+		}
+	})
+
+	t.Run("invalid client credentials", func(t *testing.T) {
+		client, auth, _, _ := setup(t)
+		auth.ClientSecret = "wrong"
+
+		if _, err := client.Do(context.Background(), op, NewRequest(), nil); err == nil {
+			t.Fatal("expected an error fetching the token")
+		}
+	})
+}
+
+func TestRetries(t *testing.T) {
+	var mu sync.Mutex
+	var attempts int
+	var failures []int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		attempts++
+		if len(failures) > 0 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(failures[0])
+			failures = failures[1:]
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	do := func(method string, maxRetries int, fail ...int) (int, error) {
+		mu.Lock()
+		attempts, failures = 0, fail
+		mu.Unlock()
+
+		client := NewClient(server.URL)
+		client.MaxRetries = maxRetries
+		client.RetryWaitMin = time.Millisecond
+		_, err := client.Do(context.Background(), &Operation{
+			Method: method,
+			Path:   "/example",
+			Retry:  []int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+		}, NewRequest(), nil)
+
+		mu.Lock()
+		defer mu.Unlock()
+		return attempts, err
+	}
+
+	t.Run("retries idempotent operations", func(t *testing.T) {
+		attempts, err := do(http.MethodGet, 3, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if attempts != 3 {
+			t.Errorf("expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("gives up after the maximum retries", func(t *testing.T) {
+		attempts, err := do(http.MethodPut, 2, 503, 503, 503, 503)
+		if apiErr, ok := err.(*APIError); !ok || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("expected an unavailable error, got %v", err)
+		}
+		if attempts != 3 {
+			t.Errorf("expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("only retries other operations when rate limited", func(t *testing.T) {
+		if attempts, err := do(http.MethodPost, 3, http.StatusServiceUnavailable); err == nil || attempts != 1 {
+			t.Errorf("expected a single failed attempt, got %d attempts and error %v", attempts, err)
+		}
+		if attempts, err := do(http.MethodPost, 3, http.StatusTooManyRequests); err != nil || attempts != 2 {
+			t.Errorf("expected a successful retry, got %d attempts and error %v", attempts, err)
+		}
+	})
+
+	t.Run("does not retry other status codes", func(t *testing.T) {
+		if attempts, err := do(http.MethodGet, 3, http.StatusBadGateway); err == nil || attempts != 1 {
+			t.Errorf("expected a single failed attempt, got %d attempts and error %v", attempts, err)
+		}
+	})
+
+	t.Run("backoff", func(t *testing.T) {
+		client := NewClient("")
+		client.RetryWaitMin = time.Second
+		client.RetryWaitMax = 4 * time.Second
+
+		if wait := client.backoff(0, nil); wait < 500*time.Millisecond || wait > time.Second {
+			t.Errorf("expected the first wait to be between 0.5s and 1s, got %s", wait)
+		}
+		if wait := client.backoff(5, nil); wait < 2*time.Second || wait > 4*time.Second {
+			t.Errorf("expected the wait to be limited to 4s, got %s", wait)
+		}
+
+		resp := &http.Response{Header: http.Header{}}
+		resp.Header.Set("Retry-After", "3")
+		if wait := client.backoff(0, resp); wait != 3*time.Second {
+			t.Errorf("expected Retry-After to be respected, got %s", wait)
+		}
+
+		resp.Header.Set("Retry-After", "3600")
+		if wait := client.backoff(0, resp); wait != 4*time.Second {
+			t.Errorf("expected Retry-After to be limited to 4s, got %s", wait)
+		}
+	})
+
+	t.Run("does not wait past the deadline", func(t *testing.T) {
+		limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "10")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		t.Cleanup(limited.Close)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		start := time.Now()
+		client := NewClient(limited.URL)
+		_, err := client.Do(ctx, &Operation{
+			Method: http.MethodGet,
+			Path:   "/example",
+			Retry:  []int{http.StatusTooManyRequests},
+		}, NewRequest(), nil)
+
+		if apiErr, ok := err.(*APIError); !ok || apiErr.StatusCode != http.StatusTooManyRequests {
+			t.Fatalf("expected a rate limited error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("expected the failure to be returned without waiting, waited %s", elapsed)
+		}
+	})
+}
+
+func TestAsync(t *testing.T) {
+	var mu sync.Mutex
+	var polls int
+	var statuses []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/example", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "/status/1")
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("/status/1", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		status := statuses[polls]
+		polls++
+		fmt.Fprintf(w, `{"job":{"state":%q}}`, status)
+	})
+	mux.HandleFunc("/created", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "/status/2")
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprint(w, `{"id":"resource-1"}`)
+	})
+	mux.HandleFunc("/status/2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"operation-1","status":"succeeded"}`)
+	})
+	mux.HandleFunc("/deleted", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		polls++
+		switch {
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusAccepted)
+		case polls < 3:
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL)
+	op := &Operation{
+		Method: http.MethodPost,
+		Path:   "/example",
+		Async: &Async{
+			Header:        "Location",
+			StatusPointer: "/job/state",
+			Success:       []string{"done"},
+			Failure:       []string{"failed"},
+		},
+	}
+
+	poll := func(op *Operation, states ...string) (*Response, int, error) {
+		mu.Lock()
+		polls, statuses = 0, states
+		mu.Unlock()
+
+		resp, err := client.Do(context.Background(), op, NewRequest(), nil)
+
+		mu.Lock()
+		defer mu.Unlock()
+		return resp, polls, err
+	}
+
+	t.Run("polls until the status is successful", func(t *testing.T) {
+		resp, polls, err := poll(op, "running", "running", "done")
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if polls != 3 {
+			t.Errorf("expected 3 polls, got %d", polls)
+		}
+		if !resp.Accepted {
+			t.Error("expected the response to be accepted")
+		}
+	})
+
+	t.Run("does not decode the status into the result", func(t *testing.T) {
+		var result struct {
+			Id string `json:"id"`
+		}
+		resp, err := client.Do(context.Background(), &Operation{
+			Method: http.MethodPost,
+			Path:   "/created",
+			Async:  &Async{Header: "Location", Interval: time.Millisecond},
+		}, NewRequest(), &result)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if !resp.Accepted {
+			t.Error("expected the response to be accepted")
+		}
+		if result.Id != "resource-1" {
+			t.Errorf("expected the accepted resource, got %q", result.Id)
+		}
+	})
+
+	t.Run("reports a failed status", func(t *testing.T) {
+		_, _, err := poll(op, "running", "failed")
+		if asyncErr, ok := err.(*AsyncError); !ok || asyncErr.Status != "failed" {
+			t.Fatalf("expected an async error, got %v", err)
+		}
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		slow := *op
+		slow.Async = &Async{Header: "Location", StatusPointer: "/job/state", Success: []string{"done"}, Interval: time.Hour}
+		if _, err := client.Do(ctx, &slow, NewRequest(), nil); err == nil {
+			t.Fatal("expected a timeout error")
+		}
+	})
+
+	t.Run("polls a deleted resource until it is gone", func(t *testing.T) {
+		_, polls, err := poll(&Operation{Method: http.MethodDelete, Path: "/deleted", Async: &Async{Header: "Location"}})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if polls != 3 {
+			t.Errorf("expected the delete and 2 polls, got %d", polls)
+		}
+	})
+
+	t.Run("JSON pointers", func(t *testing.T) {
+		var document interface{}
+		if err := json.Unmarshal([]byte(`{"a/b":[{"c~d":"value"}]}`), &document); err != nil {
+			t.Fatal(err)
+		}
+		if value, ok := jsonPointer(document, "/a~1b/0/c~0d"); !ok || value != "value" {
+			t.Errorf("expected the escaped pointer to resolve, got %v", value)
+		}
+		if _, ok := jsonPointer(document, "/a~1b/1"); ok {
+			t.Error("expected an out of range index not to resolve")
+		}
+	})
+}
+
+func TestClientSettings(t *testing.T) {
+	var received *http.Request
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	op := &Operation{Method: http.MethodGet, Path: "/example"}
+
+	t.Run("verifies the server certificate by default", func(t *testing.T) {
+		client := NewClient(server.URL)
+		if _, err := client.Do(context.Background(), op, NewRequest(), nil); err == nil {
+			t.Fatal("expected an untrusted certificate error")
+		}
+	})
+
+	t.Run("skips verification and sends global parameters", func(t *testing.T) {
+		client := NewClient(server.URL)
+		client.SetInsecureSkipVerify(true)
+		client.GlobalParameters[Parameter{In: "header", Name: "X-Tenant"}] = "tenant"
+		client.GlobalParameters[Parameter{In: "query", Name: "page"}] = 10
+
+		req := NewRequest()
+		req.Set("query", "page", 2)
+		if _, err := client.Do(context.Background(), op, req, nil); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if received.Header.Get("X-Tenant") != "tenant" {
+			t.Errorf("expected the global header to be sent, got %v", received.Header)
+		}
+		if received.URL.Query().Get("page") != "2" {
+			t.Errorf("expected the request parameter to take precedence, got %s", received.URL.RawQuery)
+		}
+		if http.DefaultClient.Transport != nil {
+			t.Error("expected the default HTTP client to be unchanged")
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		client := NewClient(server.URL)
+		if err := client.SetTimeout("1m30s"); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if client.HTTPClient.Timeout != 90*time.Second {
+			t.Errorf("expected a 90s timeout, got %s", client.HTTPClient.Timeout)
+		}
+		if err := client.SetTimeout("soon"); err == nil {
+			t.Error("expected an invalid duration error")
+		}
+	})
+}
+
+func TestNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL)
+	op := &Operation{Method: http.MethodGet, Path: "/example", NotFound: []int{http.StatusNotFound, http.StatusGone}}
+
+	_, err := client.Do(context.Background(), op, NewRequest(), nil)
+	if !IsNotFound(op, err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+
+	if IsNotFound(&Operation{Method: http.MethodGet, Path: "/example"}, err) {
+		t.Error("expected operations without not found status codes to report other errors")
+	}
+
+	if IsNotFound(op, errors.New("connection refused")) {
+		t.Error("expected only API errors to be not found")
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL)
+	client.RetryWaitMin = time.Millisecond
+	op := &Operation{Method: http.MethodPost, Path: "/example", Retry: []int{http.StatusServiceUnavailable}, IdempotencyKey: "Idempotency-Key"}
+
+	if _, err := client.Do(context.Background(), op, NewRequest(), nil); err != nil {
+		t.Fatalf("expected the create to be retried, got %s", err)
+	}
+
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("expected the same key to be sent with each attempt, got %v", keys)
+	}
+
+	if _, err := client.Do(context.Background(), op, NewRequest(), nil); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if len(keys) != 3 || keys[2] == keys[0] {
+		t.Errorf("expected a new key for each call, got %v", keys)
+	}
+}
+
+func TestMatchesPlan(t *testing.T) {
+	type body struct {
+		Name  *string `json:"name,omitempty"`
+		State *string `json:"state,omitempty"`
+	}
+	name, other, state := "example", "other", "ready"
+
+	planned, err := toDocument(body{Name: &name})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	cases := map[string]struct {
+		current  body
+		expected bool
+	}{
+		"matching":           {body{Name: &name, State: &state}, true},
+		"different":          {body{Name: &other, State: &state}, false},
+		"not yet consistent": {body{State: &state}, false},
+	}
+
+	for description, c := range cases {
+		matches, err := matchesPlan(planned, c.current, []string{"name", "state"})
+		if err != nil || matches != c.expected {
+			t.Errorf("expected %s body to match: %t, got %t %v", description, c.expected, matches, err)
+		}
+	}
+
+	if StatusCode(&APIError{StatusCode: http.StatusNotFound}) != http.StatusNotFound || StatusCode(errors.New("refused")) != 0 {
+		t.Error("expected the status code of API errors only")
+	}
+}
+
+func TestPreconditionFailed(t *testing.T) {
+	var ifMatch string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifMatch = r.Header.Get("If-Match")
+		w.WriteHeader(http.StatusPreconditionFailed)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL)
+	req := NewRequest()
+	req.Set("header", "If-Match", `"v1"`)
+
+	_, err := client.Do(context.Background(), &Operation{Method: http.MethodDelete, Path: "/example"}, req, nil)
+	if !IsPreconditionFailed(err) {
+		t.Errorf("expected a precondition failed error, got %v", err)
+	}
+	if ifMatch != `"v1"` {
+		t.Errorf("expected the entity tag to be sent, got %q", ifMatch)
+	}
+
+	if IsPreconditionFailed(&APIError{StatusCode: http.StatusConflict}) {
+		t.Error("expected only 412 to be a precondition failure")
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"code": "invalid", "message": "Validation failed", "details": [{"field": "limits[0].cpu", "message": "must be positive"}, {"field": "/name", "message": "is required"}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL)
+	op := &Operation{
+		Method: http.MethodPost,
+		Path:   "/example",
+		Errors: &ErrorSchema{Code: "/code", Message: "/message", Details: "/details", DetailField: "/field", DetailMessage: "/message"},
+	}
+
+	_, err := client.Do(context.Background(), op, NewRequest(), nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an API error, got %v", err)
+	}
+
+	if apiErr.Code != "invalid" || apiErr.Message != "Validation failed" {
+		t.Errorf("expected the code and message to be decoded, got %q %q", apiErr.Code, apiErr.Message)
+	}
+
+	expected := []FieldError{
+		{Path: []string{"limits", "0", "cpu"}, Message: "must be positive"},
+		{Path: []string{"name"}, Message: "is required"},
+	}
+	if !reflect.DeepEqual(apiErr.Fields, expected) {
+		t.Errorf("expected field errors %v, got %v", expected, apiErr.Fields)
+	}
+
+	t.Run("undocumented errors are described by the body", func(t *testing.T) {
+		_, err := client.Do(context.Background(), &Operation{Method: http.MethodPost, Path: "/example"}, NewRequest(), nil)
+		if !errors.As(err, &apiErr) || apiErr.Message != "" || !strings.Contains(err.Error(), "Validation failed") {
+			t.Errorf("expected the response body in the error, got %v", err)
+		}
+	})
+}
+
+func TestPatch(t *testing.T) {
+	var contentType string
+	var received interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		received = nil
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("expected a JSON request body, got %s", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL)
+	prior := map[string]interface{}{"id": "1", "name": "before", "labels": map[string]interface{}{"a": "1", "b": "2"}, "note": "removed"}
+	body := map[string]interface{}{"id": "1", "name": "after", "labels": map[string]interface{}{"a": "1", "b": "3"}}
+
+	cases := map[string]struct {
+		op          *Operation
+		contentType string
+		expected    string
+	}{
+		"replace": {
+			op:          &Operation{Method: http.MethodPut, Path: "/example", MediaType: "application/json"},
+			contentType: "application/json",
+			expected:    `{"id": "1", "name": "after", "labels": {"a": "1", "b": "3"}}`,
+		},
+		"merge patch": {
+			op:          &Operation{Method: http.MethodPatch, Path: "/example", MediaType: "application/json", Patch: "merge", RequestMediaType: "application/merge-patch+json"},
+			contentType: "application/merge-patch+json",
+			expected:    `{"name": "after", "labels": {"b": "3"}, "note": null}`,
+		},
+		"json patch": {
+			op:          &Operation{Method: http.MethodPatch, Path: "/example", MediaType: "application/json", Patch: "json", RequestMediaType: "application/json-patch+json"},
+			contentType: "application/json-patch+json",
+			expected:    `[{"op": "replace", "path": "/labels/b", "value": "3"}, {"op": "replace", "path": "/name", "value": "after"}, {"op": "remove", "path": "/note"}]`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := NewRequest()
+			req.Body = body
+			req.Prior = prior
+			if _, err := client.Do(context.Background(), c.op, req, nil); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			var expected interface{}
+			if err := json.Unmarshal([]byte(c.expected), &expected); err != nil {
+				t.Fatalf("invalid expectation: %s", err)
+			}
+
+			if contentType != c.contentType {
+				t.Errorf("expected content type %s, got %s", c.contentType, contentType)
+			}
+			if !reflect.DeepEqual(received, expected) {
+				t.Errorf("expected request body %v, got %v", expected, received)
+			}
+		})
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	var received interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = nil
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("expected a JSON request body, got %s", err)
+		}
+		w.Write([]byte(`{"data": {"id": "1", "name": "after"}, "meta": {"version": 2}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL)
+	prior := map[string]interface{}{"id": "1", "name": "before"}
+	body := map[string]interface{}{"id": "1", "name": "after"}
+
+	cases := map[string]struct {
+		op       *Operation
+		expected string
+	}{
+		"replace": {
+			op:       &Operation{Method: http.MethodPut, Path: "/example", MediaType: "application/json", Envelope: "/data"},
+			expected: `{"data": {"id": "1", "name": "after"}}`,
+		},
+		"merge patch": {
+			op:       &Operation{Method: http.MethodPatch, Path: "/example", MediaType: "application/json", Patch: "merge", Envelope: "/data"},
+			expected: `{"data": {"name": "after"}}`,
+		},
+		"json patch": {
+			op:       &Operation{Method: http.MethodPatch, Path: "/example", MediaType: "application/json", Patch: "json", Envelope: "/data"},
+			expected: `[{"op": "replace", "path": "/data/name", "value": "after"}]`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := NewRequest()
+			req.Body = body
+			req.Prior = prior
+
+			var result map[string]interface{}
+			if _, err := client.Do(context.Background(), c.op, req, &result); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			var expected interface{}
+			if err := json.Unmarshal([]byte(c.expected), &expected); err != nil {
+				t.Fatalf("invalid expectation: %s", err)
+			}
+
+			if !reflect.DeepEqual(received, expected) {
+				t.Errorf("expected request body %v, got %v", expected, received)
+			}
+			if !reflect.DeepEqual(result, body) {
+				t.Errorf("expected the resource %v to be unwrapped, got %v", body, result)
+			}
+		})
+	}
+
+	missing := &Operation{Method: http.MethodPut, Path: "/example", MediaType: "application/json", Envelope: "/record"}
+	req := NewRequest()
+	req.Body = body
+	var result map[string]interface{}
+	if _, err := client.Do(context.Background(), missing, req, &result); err == nil {
+		t.Error("expected an error when the response has no envelope")
+	}
+}
+
+func TestJSONAPI(t *testing.T) {
+	var received interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = nil
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("expected a JSON request body, got %s", err)
+		}
+		w.Write([]byte(`{"data": {"type": "articles", "id": "1", "attributes": {"title": "Hello"}, "relationships": {"author": {"data": {"type": "people", "id": "9"}}, "tags": {"data": [{"type": "tags", "id": "2"}, {"type": "tags", "id": "3"}]}}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	op := &Operation{
+		Method:    http.MethodPatch,
+		Path:      "/articles/1",
+		MediaType: "application/vnd.api+json",
+		Envelope:  "/data",
+		JSONAPI: &JSONAPI{
+			Type: "articles",
+			Relationships: []Relationship{
+				{Name: "author", Attribute: "author_id", Type: "people"},
+				{Name: "tags", Attribute: "tag_ids", Type: "tags", Many: true},
+			},
+		},
+	}
+
+	body := map[string]interface{}{"id": "1", "title": "Hello", "author_id": "9", "tag_ids": []interface{}{"2", "3"}}
+	req := NewRequest()
+	req.Body = body
+
+	var result map[string]interface{}
+	if _, err := NewClient(server.URL).Do(context.Background(), op, req, &result); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	var expected interface{}
+	if err := json.Unmarshal([]byte(`{"data": {"type": "articles", "id": "1", "attributes": {"title": "Hello"}, "relationships": {"author": {"data": {"type": "people", "id": "9"}}, "tags": {"data": [{"type": "tags", "id": "2"}, {"type": "tags", "id": "3"}]}}}}`), &expected); err != nil {
+		t.Fatalf("invalid expectation: %s", err)
+	}
+
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("expected request document %v, got %v", expected, received)
+	}
+	if !reflect.DeepEqual(result, body) {
+		t.Errorf("expected the resource object %v to be flattened, got %v", body, result)
+	}
+
+	// Partial updates send the changed members of the resource object, which is identified by
+	// its type and id
+	op.Patch = "merge"
+	req = NewRequest()
+	req.Body = map[string]interface{}{"id": "1", "title": "Goodbye", "author_id": "10", "tag_ids": []interface{}{"2", "3"}}
+	req.Prior = body
+
+	if _, err := NewClient(server.URL).Do(context.Background(), op, req, nil); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"data": {"type": "articles", "id": "1", "attributes": {"title": "Goodbye"}, "relationships": {"author": {"data": {"type": "people", "id": "10"}}}}}`), &expected); err != nil {
+		t.Fatalf("invalid expectation: %s", err)
+	}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("expected patch document %v, got %v", expected, received)
+	}
+
+	// Unchanged resources are still identified
+	req.Prior = req.Body
+	if _, err := NewClient(server.URL).Do(context.Background(), op, req, nil); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if expected := map[string]interface{}{"data": map[string]interface{}{"type": "articles", "id": "1"}}; !reflect.DeepEqual(received, expected) {
+		t.Errorf("expected patch document %v, got %v", expected, received)
+	}
+}
+
+func TestFormBodies(t *testing.T) {
+	var contentType string
+	var form url.Values
+	var file string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		file = ""
+		if err := r.ParseMultipartForm(1 << 20); err != nil && err != http.ErrNotMultipart {
+			t.Errorf("expected a form request body, got %s", err)
+		}
+		form = r.Form
+		if r.MultipartForm != nil {
+			form = r.MultipartForm.Value
+			if headers := r.MultipartForm.File["file"]; len(headers) == 1 {
+				f, err := headers[0].Open()
+				if err != nil {
+					t.Fatalf("could not open the uploaded file: %s", err)
+				}
+				defer f.Close()
+
+				var contents strings.Builder
+				if _, err := io.Copy(&contents, f); err != nil {
+					t.Fatalf("could not read the uploaded file: %s", err)
+				}
+				file = headers[0].Filename + ": " + contents.String()
+			}
+		}
+		w.Write([]byte(`{"data": {"id": "1", "title": "Report"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	path := filepath.Join(t.TempDir(), "report.txt")
+	if err := os.WriteFile(path, []byte("quarterly"), 0o600); err != nil {
+		t.Fatalf("could not write file: %s", err)
+	}
+
+	body := map[string]interface{}{"title": "Report", "labels": []string{"a", "b"}, "size": 1.5, "file": path}
+	cases := map[string]struct {
+		op          *Operation
+		contentType string
+		file        string
+	}{
+		"form": {
+			op:          &Operation{Method: http.MethodPut, Path: "/documents/1", MediaType: "application/json", RequestMediaType: "application/x-www-form-urlencoded", Envelope: "/data"},
+			contentType: "application/x-www-form-urlencoded",
+		},
+		"multipart": {
+			op:          &Operation{Method: http.MethodPost, Path: "/documents", MediaType: "application/json", RequestMediaType: "multipart/form-data", Files: []string{"file"}, Envelope: "/data"},
+			contentType: "multipart/form-data",
+			file:        "report.txt: quarterly",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := NewRequest()
+			req.Body = body
+
+			var result map[string]interface{}
+			if _, err := NewClient(server.URL).Do(context.Background(), c.op, req, &result); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if !strings.HasPrefix(contentType, c.contentType) {
+				t.Errorf("expected content type %s, got %s", c.contentType, contentType)
+			}
+			if form.Get("title") != "Report" || form.Get("size") != "1.5" || !reflect.DeepEqual(form["labels"], []string{"a", "b"}) {
+				t.Errorf("expected the attributes to be sent as form fields, got %v", form)
+			}
+			if file != c.file {
+				t.Errorf("expected the uploaded file %q, got %q", c.file, file)
+			}
+			if c.file != "" && form.Get("file") != "" {
+				t.Errorf("expected the file path not to be sent, got %q", form.Get("file"))
+			}
+			if result["id"] != "1" {
+				t.Errorf("expected the response resource to be unwrapped, got %v", result)
+			}
+		})
+	}
+}
+
+func TestPointers(t *testing.T) {
+	var received interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = nil
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("expected a JSON request body, got %s", err)
+		}
+		w.Write([]byte(`{"id": "1", "spec": {"replicas": 3, "template": {"metadata": {"name": "web", "labels": ["a"]}}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	op := &Operation{
+		Method:    http.MethodPut,
+		Path:      "/deployments/1",
+		MediaType: "application/json",
+		Pointers: map[string]string{
+			"labels":   "/spec/template/metadata/labels",
+			"replicas": "/spec/replicas",
+		},
+	}
+
+	req := NewRequest()
+	req.Body = map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{"metadata": map[string]interface{}{"name": "web"}}}, "labels": []string{"a"}, "replicas": 3}
+
+	var result map[string]interface{}
+	if _, err := NewClient(server.URL).Do(context.Background(), op, req, &result); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	var expected interface{}
+	if err := json.Unmarshal([]byte(`{"spec": {"replicas": 3, "template": {"metadata": {"name": "web", "labels": ["a"]}}}}`), &expected); err != nil {
+		t.Fatalf("invalid expectation: %s", err)
+	}
+
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("expected request body %v, got %v", expected, received)
+	}
+	if result["replicas"] != float64(3) || !reflect.DeepEqual(result["labels"], []interface{}{"a"}) {
+		t.Errorf("expected the mapped properties to be flattened, got %v", result)
+	}
+
+	// The mapped properties are removed from the objects that contained them
+	var spec interface{}
+	if err := json.Unmarshal([]byte(`{"template": {"metadata": {"name": "web"}}}`), &spec); err != nil {
+		t.Fatalf("invalid expectation: %s", err)
+	}
+	if !reflect.DeepEqual(result["spec"], spec) {
+		t.Errorf("expected spec %v, got %v", spec, result["spec"])
+	}
+}
+
+func TestPointersRemoveEmptyObjects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "1", "spec": {"replicas": 3, "template": {"metadata": {"labels": ["a"]}}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	op := &Operation{
+		Method:    http.MethodGet,
+		Path:      "/deployments/1",
+		MediaType: "application/json",
+		Pointers: map[string]string{
+			"labels":   "/spec/template/metadata/labels",
+			"replicas": "/spec/replicas",
+		},
+	}
+
+	var result map[string]interface{}
+	if _, err := NewClient(server.URL).Do(context.Background(), op, NewRequest(), &result); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if _, ok := result["spec"]; ok {
+		t.Errorf("expected the objects left empty by the mapped properties to be removed, got %v", result)
+	}
+}
+
+func TestSecurityRequirements(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL)
+	client.Auth["bearer"] = &BearerAuth{Token: "token"}
+	client.Auth["key"] = &APIKeyAuth{In: "header", Name: "X-Api-Key", Value: "key"}
+	client.Auth["oauth2"] = &ClientCredentialsAuth{TokenURL: "http://127.0.0.1:0", Scopes: []string{"read"}}
+
+	do := func(security []SecurityRequirement) error {
+		received = nil
+		_, err := client.Do(context.Background(), &Operation{
+			Method:    http.MethodGet,
+			Path:      "/example",
+			MediaType: "application/json",
+			Security:  security,
+		}, NewRequest(), nil)
+		return err
+	}
+
+	t.Run("unspecified requirements send all credentials", func(t *testing.T) {
+		client := NewClient(server.URL)
+		client.Auth["bearer"] = &BearerAuth{Token: "token"}
+		client.Auth["key"] = &APIKeyAuth{In: "header", Name: "X-Api-Key", Value: "key"}
+
+		received = nil
+		if _, err := client.Do(context.Background(), &Operation{Method: http.MethodGet, Path: "/example"}, NewRequest(), nil); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if received.Get("Authorization") != "Bearer token" || received.Get("X-Api-Key") != "key" {
+			t.Errorf("expected all credentials to be sent, got %v", received)
+		}
+	})
+
+	t.Run("no requirements send no credentials", func(t *testing.T) {
+		if err := do([]SecurityRequirement{}); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if received.Get("Authorization") != "" || received.Get("X-Api-Key") != "" {
+			t.Errorf("expected no credentials to be sent, got %v", received)
+		}
+	})
+
+	t.Run("the first satisfiable requirement is used", func(t *testing.T) {
+		err := do([]SecurityRequirement{
+			{"oauth2": {"write"}},
+			{"missing": nil},
+			{"key": nil},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if received.Get("X-Api-Key") != "key" || received.Get("Authorization") != "" {
+			t.Errorf("expected only the API key to be sent, got %v", received)
+		}
+	})
+
+	t.Run("schemes required together are all sent", func(t *testing.T) {
+		if err := do([]SecurityRequirement{{"bearer": nil, "key": nil}}); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if received.Get("X-Api-Key") != "key" || received.Get("Authorization") != "Bearer token" {
+			t.Errorf("expected both credentials to be sent, got %v", received)
+		}
+	})
+
+	t.Run("optional credentials", func(t *testing.T) {
+		if err := do([]SecurityRequirement{{}, {"bearer": nil}}); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if received.Get("Authorization") != "Bearer token" {
+			t.Errorf("expected configured credentials to be preferred, got %v", received)
+		}
+	})
+
+	t.Run("unsatisfiable requirements", func(t *testing.T) {
+		err := do([]SecurityRequirement{{"oauth2": {"admin"}}, {"missing": nil}})
+
+		securityErr, ok := err.(*SecurityError)
+		if !ok {
+			t.Fatalf("expected a security error, got %v", err)
+		}
+		if received != nil {
+			t.Error("expected no request to be sent")
+		}
+
+		expected := "the configured credentials cannot satisfy the security requirements of GET /example, which requires oauth2 (scopes admin), or missing"
+		if securityErr.Error() != expected {
+			t.Errorf("expected %q, got %q", expected, securityErr.Error())
+		}
+
+		if ErrorSummary(err) != "Missing Credentials" {
+			t.Errorf("unexpected error summary %q", ErrorSummary(err))
+		}
+	})
+}
@@ -0,0 +1,133 @@
+// Tests of the provider generated from test-fixtures/async.yaml, where create reports its
+// status at the Operation-Location URL, update reports it in the status field, and delete
+// completes when the cluster is gone
+package provider
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// newClustersAPI serves a cluster that is created, updated and deleted asynchronously. Each
+// operation is running when it is first polled, and has the status given by finish after.
+func newClustersAPI(t *testing.T, finish func(r *testRequest) string) *testAPI {
+	var mu sync.Mutex
+	polls := make(map[string]int)
+	deleted := false
+	cluster := map[string]interface{}{"id": "cluster-1", "name": "example", "size": 3}
+
+	return newTestAPI(t, func(w http.ResponseWriter, r *testRequest) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.Path == "/clusters":
+			w.Header().Set("Operation-Location", "/operations/create")
+			respond(w, http.StatusAccepted, cluster)
+		case r.Method == http.MethodPut && r.Path == "/clusters/cluster-1":
+			cluster["name"] = r.JSON(t).(map[string]interface{})["name"]
+			w.Header().Set("Location", "/operations/update")
+			respond(w, http.StatusAccepted, nil)
+		case r.Method == http.MethodDelete && r.Path == "/clusters/cluster-1":
+			deleted = true
+			respond(w, http.StatusAccepted, nil)
+		case r.Method == http.MethodGet && r.Path == "/clusters/cluster-1":
+			if deleted {
+				polls[r.Path]++
+				if polls[r.Path] > 1 {
+					respond(w, http.StatusNotFound, nil)
+					return
+				}
+			}
+			respond(w, http.StatusOK, cluster)
+		case r.Method == http.MethodGet && (r.Path == "/operations/create" || r.Path == "/operations/update"):
+			// The status describes the operation, and has its own identity and name
+			polls[r.Path]++
+			status := map[string]interface{}{"id": "operation-1", "name": "operation", "status": "running"}
+			if polls[r.Path] == 1 {
+				respond(w, http.StatusAccepted, status)
+				return
+			}
+			status["status"] = finish(r)
+			respond(w, http.StatusOK, status)
+		default:
+			respond(w, http.StatusNotFound, nil)
+		}
+	})
+}
+
+func createCluster(t *testing.T, p *testProvider) *testState {
+	t.Helper()
+
+	state, diags := p.Apply("example_clusters", nil, map[string]tftypes.Value{"name": str("example"), "size": num(3)})
+	requireNoErrors(t, nil, diags)
+	return state
+}
+
+func TestAsyncCreate(t *testing.T) {
+	api := newClustersAPI(t, func(*testRequest) string { return "succeeded" })
+	p := newTestProvider(t, api, nil)
+
+	state := createCluster(t, p)
+
+	if id := state.String(t, "id"); id != "cluster-1" {
+		t.Errorf("expected the identity of the cluster, got %q", id)
+	}
+	if name := state.String(t, "name"); name != "example" {
+		t.Errorf("expected the name of the cluster, got %q", name)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 4 || requests[3].Method != http.MethodGet || requests[3].Path != "/clusters/cluster-1" {
+		t.Errorf("expected the cluster to be read once the operation completed, got %s", describeRequests(requests))
+	}
+}
+
+func TestAsyncUpdate(t *testing.T) {
+	api := newClustersAPI(t, func(*testRequest) string { return "succeeded" })
+	p := newTestProvider(t, api, nil)
+	state := createCluster(t, p)
+
+	state, diags := p.Apply("example_clusters", state, map[string]tftypes.Value{"name": str("renamed"), "size": num(3)})
+	requireNoErrors(t, nil, diags)
+
+	if id := state.String(t, "id"); id != "cluster-1" {
+		t.Errorf("expected the identity of the cluster, got %q", id)
+	}
+	if name := state.String(t, "name"); name != "renamed" {
+		t.Errorf("expected the updated name, got %q", name)
+	}
+}
+
+func TestAsyncUpdateFailure(t *testing.T) {
+	api := newClustersAPI(t, func(r *testRequest) string {
+		if r.Path == "/operations/update" {
+			return "failed"
+		}
+		return "succeeded"
+	})
+	p := newTestProvider(t, api, nil)
+	state := createCluster(t, p)
+
+	_, diags := p.Apply("example_clusters", state, map[string]tftypes.Value{"name": str("renamed"), "size": num(3)})
+	if findError(diags, `failed with status "failed"`) == nil {
+		t.Errorf("expected the failed operation to be an error, got:\n%s", describeDiagnostics(diags))
+	}
+}
+
+func TestAsyncDelete(t *testing.T) {
+	api := newClustersAPI(t, func(*testRequest) string { return "succeeded" })
+	p := newTestProvider(t, api, nil)
+	state := createCluster(t, p)
+	api.Requests()
+
+	requireNoErrors(t, nil, p.Destroy("example_clusters", state))
+
+	requests := api.Requests()
+	if len(requests) != 3 {
+		t.Errorf("expected the cluster to be polled until it was gone, got %s", describeRequests(requests))
+	}
+}
@@ -0,0 +1,100 @@
+// Tests of the provider generated from test-fixtures/security.yaml, configured to read widgets
+// after each change until their names match the plan
+package provider
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// widgetsAPI serves a widget that is read as it was before each change until it has been read
+// lag times
+type widgetsAPI struct {
+	*testAPI
+
+	mu      sync.Mutex
+	lag     int
+	reads   int
+	current map[string]interface{}
+	stale   map[string]interface{}
+}
+
+func newWidgetsAPI(t *testing.T, lag int) *widgetsAPI {
+	api := &widgetsAPI{lag: lag}
+	api.testAPI = newTestAPI(t, func(w http.ResponseWriter, r *testRequest) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.Path == "/widgets",
+			r.Method == http.MethodPut && r.Path == "/widgets/widget-1":
+			api.stale, api.current = api.current, r.JSON(t).(map[string]interface{})
+			api.current["id"] = "widget-1"
+			api.reads = 0
+			respond(w, http.StatusOK, api.current)
+		case r.Method == http.MethodGet && r.Path == "/widgets/widget-1" && api.reads < api.lag:
+			api.reads++
+			if api.stale == nil {
+				respond(w, http.StatusNotFound, nil)
+				return
+			}
+			respond(w, http.StatusOK, api.stale)
+		case r.Method == http.MethodGet && r.Path == "/widgets/widget-1":
+			respond(w, http.StatusOK, api.current)
+		default:
+			respond(w, http.StatusBadRequest, nil)
+		}
+	})
+	return api
+}
+
+func TestConsistencyCreate(t *testing.T) {
+	api := newWidgetsAPI(t, 2)
+	p := newTestProvider(t, api.testAPI, nil)
+
+	state, diags := p.Apply("example_widgets", nil, map[string]tftypes.Value{"name": str("example")})
+	requireNoErrors(t, nil, diags)
+
+	if id := state.String(t, "id"); id != "widget-1" {
+		t.Errorf("expected the created widget, got %q", id)
+	}
+	if requests := api.Requests(); len(requests) != 4 {
+		t.Errorf("expected the widget to be read until it was found, got %s", describeRequests(requests))
+	}
+}
+
+func TestConsistencyUpdate(t *testing.T) {
+	api := newWidgetsAPI(t, 0)
+	p := newTestProvider(t, api.testAPI, nil)
+
+	state, diags := p.Apply("example_widgets", nil, map[string]tftypes.Value{"name": str("example")})
+	requireNoErrors(t, nil, diags)
+	api.Requests()
+
+	api.mu.Lock()
+	api.lag = 2
+	api.mu.Unlock()
+
+	state, diags = p.Apply("example_widgets", state, map[string]tftypes.Value{"name": str("renamed")})
+	requireNoErrors(t, nil, diags)
+
+	if name := state.String(t, "name"); name != "renamed" {
+		t.Errorf("expected the updated name, got %q", name)
+	}
+	if requests := api.Requests(); len(requests) != 4 {
+		t.Errorf("expected the widget to be read until its name matched the plan, got %s", describeRequests(requests))
+	}
+}
+
+func TestConsistencyTimeout(t *testing.T) {
+	api := newWidgetsAPI(t, 1000)
+	p := newTestProvider(t, api.testAPI, nil)
+
+	_, diags := p.Apply("example_widgets", nil, map[string]tftypes.Value{"name": str("example")})
+	if findError(diags, "the API was not consistent after 500ms") == nil {
+		t.Errorf("expected a widget that is never found to be an error, got:\n%s", describeDiagnostics(diags))
+	}
+}
@@ -0,0 +1,67 @@
+// Tests of the provider generated from test-fixtures/envelope.yaml, where projects are sent
+// and received within the data property of the request and response bodies, whether the
+// envelope is probed or configured
+package provider
+
+import (
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestEnvelope(t *testing.T) {
+	var mu sync.Mutex
+	project := map[string]interface{}{}
+
+	api := newTestAPI(t, func(w http.ResponseWriter, r *testRequest) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.Path == "/projects",
+			r.Method == http.MethodPut && r.Path == "/projects/project-1":
+			project = r.JSON(t).(map[string]interface{})["data"].(map[string]interface{})
+			project["id"] = "project-1"
+			fallthrough
+		case r.Method == http.MethodGet && r.Path == "/projects/project-1":
+			respond(w, http.StatusOK, map[string]interface{}{
+				"data": project,
+				"meta": map[string]interface{}{"request_id": "request-1"},
+			})
+		default:
+			respond(w, http.StatusBadRequest, nil)
+		}
+	})
+	p := newTestProvider(t, api, nil)
+
+	state, diags := p.Apply("example_projects", nil, map[string]tftypes.Value{"name": str("example")})
+	requireNoErrors(t, nil, diags)
+
+	expected := map[string]interface{}{"data": map[string]interface{}{"name": "example"}}
+	if body := api.Request(t).JSON(t); !reflect.DeepEqual(body, expected) {
+		t.Errorf("expected the project to be sent within the envelope, got %v", body)
+	}
+	if id := state.String(t, "id"); id != "project-1" {
+		t.Errorf("expected the project to be received within the envelope, got id %q", id)
+	}
+
+	state, diags = p.Apply("example_projects", state, map[string]tftypes.Value{"name": str("renamed"), "description": str("text")})
+	requireNoErrors(t, nil, diags)
+
+	body := api.Request(t).JSON(t).(map[string]interface{})
+	if data, ok := body["data"].(map[string]interface{}); !ok || data["name"] != "renamed" || data["description"] != "text" {
+		t.Errorf("expected the project to be sent within the envelope, got %v", body)
+	}
+	if description := state.String(t, "description"); description != "text" {
+		t.Errorf("expected the project to be received within the envelope, got description %q", description)
+	}
+
+	state, diags = p.Read("example_projects", state)
+	requireNoErrors(t, nil, diags)
+	if name := state.String(t, "name"); name != "renamed" {
+		t.Errorf("expected the project to be read within the envelope, got name %q", name)
+	}
+}
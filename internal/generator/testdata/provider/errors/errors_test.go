@@ -0,0 +1,89 @@
+// Tests of the provider generated from test-fixtures/security.yaml, whose read and update
+// operations document different error responses
+package provider
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// newWidgetsAPI serves a widget, which fails to be read or updated once the API is broken
+func newWidgetsAPI(t *testing.T) (*testAPI, func()) {
+	var mu sync.Mutex
+	broken := false
+
+	api := newTestAPI(t, func(w http.ResponseWriter, r *testRequest) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		widget := map[string]interface{}{"id": "widget-1", "name": "example"}
+		switch {
+		case r.Method == http.MethodPost && r.Path == "/widgets":
+			respond(w, http.StatusCreated, widget)
+		case r.Method == http.MethodGet && r.Path == "/widgets/widget-1" && broken:
+			respond(w, http.StatusInternalServerError, map[string]interface{}{
+				"error": map[string]interface{}{"code": "unavailable", "message": "Storage is unavailable"},
+			})
+		case r.Method == http.MethodGet && r.Path == "/widgets/widget-1":
+			respond(w, http.StatusOK, widget)
+		case r.Method == http.MethodPut && r.Path == "/widgets/widget-1":
+			respond(w, http.StatusUnprocessableEntity, map[string]interface{}{
+				"code":    "invalid",
+				"message": "The widget is invalid",
+				"details": []interface{}{
+					map[string]interface{}{"field": "name", "message": "must be unique"},
+				},
+			})
+		default:
+			respond(w, http.StatusBadRequest, nil)
+		}
+	})
+
+	return api, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		broken = true
+	}
+}
+
+func createWidget(t *testing.T, p *testProvider) *testState {
+	t.Helper()
+
+	state, diags := p.Apply("example_widgets", nil, map[string]tftypes.Value{"name": str("example")})
+	requireNoErrors(t, nil, diags)
+	return state
+}
+
+func TestErrorMessage(t *testing.T) {
+	api, breakAPI := newWidgetsAPI(t)
+	p := newTestProvider(t, api, nil)
+	state := createWidget(t, p)
+
+	breakAPI()
+	_, diags := p.Read("example_widgets", state)
+	if findError(diags, "Storage is unavailable (unavailable)") == nil {
+		t.Errorf("expected the error message of the API, got:\n%s", describeDiagnostics(diags))
+	}
+}
+
+func TestErrorFields(t *testing.T) {
+	api, _ := newWidgetsAPI(t)
+	p := newTestProvider(t, api, nil)
+	state := createWidget(t, p)
+
+	_, diags := p.Apply("example_widgets", state, map[string]tftypes.Value{"name": str("duplicate")})
+	if findError(diags, "The widget is invalid (invalid)") == nil {
+		t.Errorf("expected the error message of the API, got:\n%s", describeDiagnostics(diags))
+	}
+
+	diag := findError(diags, "must be unique")
+	if diag == nil {
+		t.Fatalf("expected the rejected attribute to be an error, got:\n%s", describeDiagnostics(diags))
+	}
+	if expected := tftypes.NewAttributePath().WithAttributeName("name"); !diag.Attribute.Equal(expected) {
+		t.Errorf("expected the error to be located at %s, got %s", expected, diag.Attribute)
+	}
+}
@@ -0,0 +1,157 @@
+// Tests of the provider generated from test-fixtures/patch.yaml, where notes are updated and
+// deleted only if they were not changed since they were read, and tags unconditionally
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// notesAPI serves a note whose entity tag changes with each update
+type notesAPI struct {
+	*testAPI
+
+	mu      sync.Mutex
+	version int
+	title   string
+
+	// untagged omits the entity tag from update responses
+	untagged bool
+}
+
+func newNotesAPI(t *testing.T) *notesAPI {
+	api := &notesAPI{version: 1, title: "example"}
+	api.testAPI = newTestAPI(t, func(w http.ResponseWriter, r *testRequest) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+
+		note := map[string]interface{}{"id": "note-1", "title": api.title}
+		etag := fmt.Sprintf(`"v%d"`, api.version)
+		switch {
+		case r.Method == http.MethodPost && r.Path == "/notes":
+			w.Header().Set("ETag", etag)
+			respond(w, http.StatusCreated, note)
+		case r.Method == http.MethodGet && r.Path == "/notes/note-1":
+			w.Header().Set("ETag", etag)
+			respond(w, http.StatusOK, note)
+		case r.Header.Get("If-Match") != "" && r.Header.Get("If-Match") != etag:
+			respond(w, http.StatusPreconditionFailed, nil)
+		case r.Method == http.MethodPatch && r.Path == "/notes/note-1":
+			api.version++
+			if !api.untagged {
+				w.Header().Set("ETag", fmt.Sprintf(`"v%d"`, api.version))
+			}
+			api.title = r.JSON(t).(map[string]interface{})["title"].(string)
+			note["title"] = api.title
+			respond(w, http.StatusOK, note)
+		case r.Method == http.MethodDelete && r.Path == "/notes/note-1":
+			respond(w, http.StatusNoContent, nil)
+		case r.Method == http.MethodPost && r.Path == "/tags":
+			respond(w, http.StatusCreated, map[string]interface{}{"id": "tag-1", "name": "example"})
+		case r.Path == "/tags/tag-1":
+			respond(w, http.StatusOK, map[string]interface{}{"id": "tag-1", "name": "example"})
+		default:
+			respond(w, http.StatusBadRequest, nil)
+		}
+	})
+	return api
+}
+
+// change updates the note outside terraform
+func (a *notesAPI) change() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.version++
+}
+
+func createNote(t *testing.T, p *testProvider, api *notesAPI) *testState {
+	t.Helper()
+
+	state, diags := p.Apply("example_notes", nil, map[string]tftypes.Value{"title": str("example")})
+	requireNoErrors(t, nil, diags)
+	api.Requests()
+	return state
+}
+
+func updateNote(t *testing.T, p *testProvider, api *notesAPI, state *testState, title string) (*testState, string) {
+	t.Helper()
+
+	state, diags := p.Apply("example_notes", state, map[string]tftypes.Value{"title": str(title)})
+	requireNoErrors(t, nil, diags)
+
+	requests := api.Requests()
+	if len(requests) == 0 || requests[0].Method != http.MethodPatch {
+		t.Fatalf("expected the note to be updated, got %s", describeRequests(requests))
+	}
+	return state, requests[0].Header.Get("If-Match")
+}
+
+func TestETagConditionalUpdates(t *testing.T) {
+	api := newNotesAPI(t)
+	p := newTestProvider(t, api.testAPI, nil)
+	state := createNote(t, p, api)
+
+	state, ifMatch := updateNote(t, p, api, state, "first")
+	if ifMatch != `"v1"` {
+		t.Errorf("expected the update to be conditional on the created note, got If-Match %q", ifMatch)
+	}
+
+	state, ifMatch = updateNote(t, p, api, state, "second")
+	if ifMatch != `"v2"` {
+		t.Errorf("expected the update to be conditional on the updated note, got If-Match %q", ifMatch)
+	}
+
+	requireNoErrors(t, nil, p.Destroy("example_notes", state))
+	if ifMatch := api.Request(t).Header.Get("If-Match"); ifMatch != `"v3"` {
+		t.Errorf("expected the delete to be conditional on the updated note, got If-Match %q", ifMatch)
+	}
+}
+
+func TestETagMissingFromUpdate(t *testing.T) {
+	api := newNotesAPI(t)
+	api.untagged = true
+	p := newTestProvider(t, api.testAPI, nil)
+	state := createNote(t, p, api)
+
+	state, _ = updateNote(t, p, api, state, "first")
+	_, ifMatch := updateNote(t, p, api, state, "second")
+	if ifMatch != `"v2"` {
+		t.Errorf("expected the note to be read for its entity tag, got If-Match %q", ifMatch)
+	}
+}
+
+func TestETagChangedOutsideTerraform(t *testing.T) {
+	api := newNotesAPI(t)
+	p := newTestProvider(t, api.testAPI, nil)
+	state := createNote(t, p, api)
+
+	api.change()
+	_, diags := p.Apply("example_notes", state, map[string]tftypes.Value{"title": str("renamed")})
+	if findError(diags, "Resource Changed Outside Terraform") == nil {
+		t.Errorf("expected the changed note not to be updated, got:\n%s", describeDiagnostics(diags))
+	}
+
+	diags = p.Destroy("example_notes", state)
+	if findError(diags, "Resource Changed Outside Terraform") == nil {
+		t.Errorf("expected the changed note not to be deleted, got:\n%s", describeDiagnostics(diags))
+	}
+}
+
+func TestETagUnconditional(t *testing.T) {
+	api := newNotesAPI(t)
+	p := newTestProvider(t, api.testAPI, nil)
+
+	state, diags := p.Apply("example_tags", nil, map[string]tftypes.Value{"name": str("example")})
+	requireNoErrors(t, nil, diags)
+	requireNoErrors(t, nil, p.Destroy("example_tags", state))
+
+	for _, req := range api.Requests() {
+		if ifMatch := req.Header.Get("If-Match"); ifMatch != "" {
+			t.Errorf("expected %s %s to be unconditional, got If-Match %q", req.Method, req.Path, ifMatch)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Tests of the provider generated from test-fixtures/forms.yaml, where documents are created
+// with a multipart form that uploads their file, and updated with a URL encoded form
+package provider
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// parseForm parses the form of the request body, with its uploaded files
+func parseForm(t *testing.T, r *testRequest) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(r.Method, r.Path, bytes.NewReader(r.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header = r.Header
+	if err = req.ParseMultipartForm(1 << 20); err != nil && err != http.ErrNotMultipart {
+		t.Fatalf("invalid form: %s", err)
+	}
+	return req
+}
+
+func TestForms(t *testing.T) {
+	var mu sync.Mutex
+	var forms []*http.Request
+	document := map[string]interface{}{"id": "document-1", "size": 0}
+
+	api := newTestAPI(t, func(w http.ResponseWriter, r *testRequest) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.Path == "/documents",
+			r.Method == http.MethodPut && r.Path == "/documents/document-1":
+			form := parseForm(t, r)
+			forms = append(forms, form)
+			document["title"] = form.FormValue("title")
+			document["labels"] = form.Form["labels"]
+			if form.MultipartForm != nil && len(form.MultipartForm.File["file"]) > 0 {
+				document["size"] = form.MultipartForm.File["file"][0].Size
+			}
+			fallthrough
+		case r.Method == http.MethodGet && r.Path == "/documents/document-1":
+			respond(w, http.StatusOK, document)
+		default:
+			respond(w, http.StatusBadRequest, nil)
+		}
+	})
+	p := newTestProvider(t, api, nil)
+
+	file := filepath.Join(t.TempDir(), "document.txt")
+	if err := os.WriteFile(file, []byte("contents"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	state, diags := p.Apply("example_documents", nil, map[string]tftypes.Value{
+		"title":  str("example"),
+		"labels": strs("a", "b"),
+		"file":   str(file),
+	})
+	requireNoErrors(t, nil, diags)
+
+	if len(forms) != 1 || forms[0].MultipartForm == nil {
+		t.Fatalf("expected the document to be created with a multipart form, got %d forms", len(forms))
+	}
+	created := forms[0]
+	if title := created.FormValue("title"); title != "example" {
+		t.Errorf("expected the title field, got %q", title)
+	}
+	if labels := created.Form["labels"]; !reflect.DeepEqual(labels, []string{"a", "b"}) {
+		t.Errorf("expected a labels field for each label, got %q", labels)
+	}
+	if uploaded := created.MultipartForm.File["file"]; len(uploaded) != 1 || uploaded[0].Filename != "document.txt" {
+		t.Errorf("expected the file to be uploaded, got %v", uploaded)
+	} else {
+		f, _ := uploaded[0].Open()
+		contents, _ := io.ReadAll(f)
+		f.Close()
+		if string(contents) != "contents" {
+			t.Errorf("expected the contents of the file to be uploaded, got %q", contents)
+		}
+	}
+
+	// The response describes the document rather than the uploaded file, whose path is kept
+	if path := state.String(t, "file"); path != file {
+		t.Errorf("expected the path of the file to be kept, got %q", path)
+	}
+
+	state, diags = p.Apply("example_documents", state, map[string]tftypes.Value{
+		"title":  str("renamed"),
+		"labels": strs("c"),
+		"file":   str(file),
+	})
+	requireNoErrors(t, nil, diags)
+
+	updated := forms[len(forms)-1]
+	if contentType := updated.Header.Get("Content-Type"); contentType != "application/x-www-form-urlencoded" {
+		t.Errorf("expected the document to be updated with a URL encoded form, got %s", contentType)
+	}
+	if title := updated.FormValue("title"); title != "renamed" {
+		t.Errorf("expected the title field, got %q", title)
+	}
+	if path := state.String(t, "file"); path != file {
+		t.Errorf("expected the path of the file to be kept, got %q", path)
+	}
+
+	state, diags = p.Read("example_documents", state)
+	requireNoErrors(t, nil, diags)
+	if path := state.String(t, "file"); path != file {
+		t.Errorf("expected the path of the file to be kept when the document is read, got %q", path)
+	}
+}
@@ -0,0 +1,115 @@
+// Tests of the provider generated from test-fixtures/patch.yaml, where notes are created with
+// an Idempotency-Key header
+package provider
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// newNotesAPI serves a note. The connection of the first create call is closed without a
+// response, as if it failed after the note was created, when drop is true.
+func newNotesAPI(t *testing.T, drop bool) *testAPI {
+	var mu sync.Mutex
+	note := map[string]interface{}{"id": "note-1"}
+
+	return newTestAPI(t, func(w http.ResponseWriter, r *testRequest) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.Path == "/notes" && drop:
+			drop = false
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			conn.Close()
+		case r.Method == http.MethodPost && r.Path == "/notes",
+			r.Method == http.MethodPatch && r.Path == "/notes/note-1":
+			for name, value := range r.JSON(t).(map[string]interface{}) {
+				note[name] = value
+			}
+			w.Header().Set("ETag", `"v1"`)
+			respond(w, http.StatusOK, note)
+		case r.Method == http.MethodGet && r.Path == "/notes/note-1":
+			w.Header().Set("ETag", `"v1"`)
+			respond(w, http.StatusOK, note)
+		default:
+			respond(w, http.StatusBadRequest, nil)
+		}
+	})
+}
+
+// createKeys returns the idempotency keys of the create calls
+func createKeys(api *testAPI) []string {
+	result := make([]string, 0)
+	for _, req := range api.Requests() {
+		if req.Method == http.MethodPost {
+			result = append(result, req.Header.Get("Idempotency-Key"))
+		}
+	}
+	return result
+}
+
+func TestIdempotencyKeyRetried(t *testing.T) {
+	api := newNotesAPI(t, true)
+	p := newTestProvider(t, api, nil)
+
+	state, diags := p.Apply("example_notes", nil, map[string]tftypes.Value{"title": str("example")})
+	requireNoErrors(t, nil, diags)
+
+	keys := createKeys(api)
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Fatalf("expected the create call to be retried with the same key, got %q", keys)
+	}
+	if key := state.String(t, "idempotency_key"); key != keys[0] {
+		t.Errorf("expected the key %q to be kept in state, got %q", keys[0], key)
+	}
+}
+
+func TestIdempotencyKeyKept(t *testing.T) {
+	api := newNotesAPI(t, false)
+	p := newTestProvider(t, api, nil)
+
+	state, diags := p.Apply("example_notes", nil, map[string]tftypes.Value{"title": str("example")})
+	requireNoErrors(t, nil, diags)
+	key := state.String(t, "idempotency_key")
+
+	state, diags = p.Read("example_notes", state)
+	requireNoErrors(t, nil, diags)
+	if actual := state.String(t, "idempotency_key"); actual != key {
+		t.Errorf("expected the key %q to be kept when the note is read, got %q", key, actual)
+	}
+
+	state, diags = p.Apply("example_notes", state, map[string]tftypes.Value{"title": str("renamed")})
+	requireNoErrors(t, nil, diags)
+	if actual := state.String(t, "idempotency_key"); actual != key {
+		t.Errorf("expected the key %q to be kept when the note is updated, got %q", key, actual)
+	}
+
+	other, diags := p.Apply("example_notes", nil, map[string]tftypes.Value{"title": str("other")})
+	requireNoErrors(t, nil, diags)
+	if actual := other.String(t, "idempotency_key"); actual == key {
+		t.Errorf("expected each note to be created with its own key, got %q twice", key)
+	}
+}
+
+func TestIdempotencyKeyConfigured(t *testing.T) {
+	api := newNotesAPI(t, false)
+	p := newTestProvider(t, api, nil)
+
+	state, diags := p.Apply("example_notes", nil, map[string]tftypes.Value{"title": str("example"), "idempotency_key": str("key-1")})
+	requireNoErrors(t, nil, diags)
+
+	if keys := createKeys(api); len(keys) != 1 || keys[0] != "key-1" {
+		t.Errorf("expected the note to be created with the configured key, got %q", keys)
+	}
+	if key := state.String(t, "idempotency_key"); key != "key-1" {
+		t.Errorf("expected the configured key to be kept in state, got %q", key)
+	}
+}
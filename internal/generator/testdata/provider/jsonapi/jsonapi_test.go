@@ -0,0 +1,125 @@
+// Tests of the provider generated from test-fixtures/jsonapi.yaml, where articles are JSON:API
+// resource objects whose author and tags are relationships
+package provider
+
+import (
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// newArticlesAPI serves an article, keeping the resource object it is created or updated with
+func newArticlesAPI(t *testing.T) *testAPI {
+	var mu sync.Mutex
+	article := map[string]interface{}{}
+
+	return newTestAPI(t, func(w http.ResponseWriter, r *testRequest) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.Path == "/articles":
+			article = r.JSON(t).(map[string]interface{})["data"].(map[string]interface{})
+			article["id"] = "article-1"
+			respond(w, http.StatusCreated, map[string]interface{}{"data": article})
+		case r.Method == http.MethodPatch && r.Path == "/articles/article-1":
+			data := r.JSON(t).(map[string]interface{})["data"].(map[string]interface{})
+			for name, value := range data["attributes"].(map[string]interface{}) {
+				article["attributes"].(map[string]interface{})[name] = value
+			}
+			if relationships, ok := data["relationships"].(map[string]interface{}); ok {
+				for name, value := range relationships {
+					article["relationships"].(map[string]interface{})[name] = value
+				}
+			}
+			fallthrough
+		case r.Method == http.MethodGet && r.Path == "/articles/article-1":
+			respond(w, http.StatusOK, map[string]interface{}{"data": article})
+		default:
+			respond(w, http.StatusBadRequest, nil)
+		}
+	})
+}
+
+func TestJSONAPI(t *testing.T) {
+	api := newArticlesAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	state, diags := p.Apply("example_articles", nil, map[string]tftypes.Value{
+		"title":     str("example"),
+		"author_id": str("person-1"),
+		"tag_ids":   strs("tag-1", "tag-2"),
+	})
+	requireNoErrors(t, nil, diags)
+
+	req := api.Request(t)
+	if contentType := req.Header.Get("Content-Type"); contentType != "application/vnd.api+json" {
+		t.Errorf("expected a JSON:API document, got %s", contentType)
+	}
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"type":       "articles",
+			"attributes": map[string]interface{}{"title": "example"},
+			"relationships": map[string]interface{}{
+				"author": map[string]interface{}{
+					"data": map[string]interface{}{"type": "people", "id": "person-1"},
+				},
+				"tags": map[string]interface{}{
+					"data": []interface{}{
+						map[string]interface{}{"type": "tags", "id": "tag-1"},
+						map[string]interface{}{"type": "tags", "id": "tag-2"},
+					},
+				},
+			},
+		},
+	}
+	if body := req.JSON(t); !reflect.DeepEqual(body, expected) {
+		t.Errorf("expected the article to be sent as a resource object, got %v", body)
+	}
+
+	if id := state.String(t, "id"); id != "article-1" {
+		t.Errorf("expected the identity of the resource object, got %q", id)
+	}
+	if author := state.String(t, "author_id"); author != "person-1" {
+		t.Errorf("expected the author relationship, got %q", author)
+	}
+	if tags := state.Attribute(t, "tag_ids"); !tags.Equal(strs("tag-1", "tag-2")) {
+		t.Errorf("expected the tags relationship, got %s", tags)
+	}
+}
+
+func TestJSONAPIUpdate(t *testing.T) {
+	api := newArticlesAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	state, diags := p.Apply("example_articles", nil, map[string]tftypes.Value{
+		"title":     str("example"),
+		"author_id": str("person-1"),
+	})
+	requireNoErrors(t, nil, diags)
+	api.Requests()
+
+	state, diags = p.Apply("example_articles", state, map[string]tftypes.Value{
+		"title":     str("renamed"),
+		"author_id": str("person-1"),
+	})
+	requireNoErrors(t, nil, diags)
+
+	// The merge patch keeps the type and id of the resource object
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"type":       "articles",
+			"id":         "article-1",
+			"attributes": map[string]interface{}{"title": "renamed"},
+		},
+	}
+	if body := api.Request(t).JSON(t); !reflect.DeepEqual(body, expected) {
+		t.Errorf("expected only the changes to be sent, got %v", body)
+	}
+	if title := state.String(t, "title"); title != "renamed" {
+		t.Errorf("expected the updated title, got %q", title)
+	}
+}
@@ -0,0 +1,76 @@
+// Tests of the provider generated from test-fixtures/mapping.yaml, configured so that the
+// replicas and labels nested within the spec of deployments are top level attributes
+package provider
+
+import (
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestAttributeMapping(t *testing.T) {
+	var mu sync.Mutex
+	deployment := map[string]interface{}{}
+
+	api := newTestAPI(t, func(w http.ResponseWriter, r *testRequest) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.Path == "/deployments",
+			r.Method == http.MethodPut && r.Path == "/deployments/deployment-1":
+			deployment = r.JSON(t).(map[string]interface{})
+			deployment["id"] = "deployment-1"
+			fallthrough
+		case r.Method == http.MethodGet && r.Path == "/deployments/deployment-1":
+			respond(w, http.StatusOK, deployment)
+		default:
+			respond(w, http.StatusBadRequest, nil)
+		}
+	})
+	p := newTestProvider(t, api, nil)
+
+	state, diags := p.Apply("example_deployments", nil, map[string]tftypes.Value{
+		"name":     str("example"),
+		"replicas": num(3),
+		"labels":   strs("a", "b"),
+	})
+	requireNoErrors(t, nil, diags)
+
+	expected := map[string]interface{}{
+		"name": "example",
+		"spec": map[string]interface{}{
+			"replicas": float64(3),
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": []interface{}{"a", "b"}},
+			},
+		},
+	}
+	if body := api.Request(t).JSON(t); !reflect.DeepEqual(body, expected) {
+		t.Errorf("expected the attributes to be sent at their pointers, got %v", body)
+	}
+
+	state, diags = p.Apply("example_deployments", state, map[string]tftypes.Value{
+		"name":     str("example"),
+		"replicas": num(5),
+		"labels":   strs("a", "b"),
+	})
+	requireNoErrors(t, nil, diags)
+
+	body := api.Request(t).JSON(t).(map[string]interface{})
+	if spec, ok := body["spec"].(map[string]interface{}); !ok || spec["replicas"] != float64(5) {
+		t.Errorf("expected the updated replicas to be sent at their pointer, got %v", body)
+	}
+
+	state, diags = p.Read("example_deployments", state)
+	requireNoErrors(t, nil, diags)
+	if replicas := state.Attribute(t, "replicas"); !replicas.Equal(num(5)) {
+		t.Errorf("expected the replicas to be read from their pointer, got %s", replicas)
+	}
+	if labels := state.Attribute(t, "labels"); !labels.Equal(strs("a", "b")) {
+		t.Errorf("expected the labels to be read from their pointer, got %s", labels)
+	}
+}
@@ -0,0 +1,76 @@
+// Tests of the provider generated from test-fixtures/security.yaml, configured so that only
+// 410 Gone from read indicates that a widget no longer exists
+package provider
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// newWidgetsAPI serves a widget, which responds with the status once it is gone
+func newWidgetsAPI(t *testing.T) (*testAPI, func(status int)) {
+	var mu sync.Mutex
+	gone := 0
+
+	api := newTestAPI(t, func(w http.ResponseWriter, r *testRequest) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case gone != 0 && r.Path == "/widgets/widget-1":
+			respond(w, gone, nil)
+		case r.Method == http.MethodPost && r.Path == "/widgets":
+			respond(w, http.StatusCreated, map[string]interface{}{"id": "widget-1", "name": "example"})
+		case r.Method == http.MethodGet && r.Path == "/widgets/widget-1":
+			respond(w, http.StatusOK, map[string]interface{}{"id": "widget-1", "name": "example"})
+		default:
+			respond(w, http.StatusBadRequest, nil)
+		}
+	})
+
+	return api, func(status int) {
+		mu.Lock()
+		defer mu.Unlock()
+		gone = status
+	}
+}
+
+func createWidget(t *testing.T, p *testProvider) *testState {
+	t.Helper()
+
+	state, diags := p.Apply("example_widgets", nil, map[string]tftypes.Value{"name": str("example")})
+	requireNoErrors(t, nil, diags)
+	return state
+}
+
+func TestNotFoundRead(t *testing.T) {
+	api, gone := newWidgetsAPI(t)
+	p := newTestProvider(t, api, nil)
+	state := createWidget(t, p)
+
+	gone(http.StatusGone)
+	removed, diags := p.Read("example_widgets", state)
+	requireNoErrors(t, nil, diags)
+	if removed != nil {
+		t.Error("expected a widget that is gone to be removed from state")
+	}
+
+	gone(http.StatusNotFound)
+	if _, diags := p.Read("example_widgets", state); findError(diags, "Unable to read widgets") == nil {
+		t.Errorf("expected a widget that was not found to be an error, got:\n%s", describeDiagnostics(diags))
+	}
+}
+
+func TestNotFoundDelete(t *testing.T) {
+	api, gone := newWidgetsAPI(t)
+	p := newTestProvider(t, api, nil)
+	state := createWidget(t, p)
+
+	gone(http.StatusNotFound)
+	if diags := p.Destroy("example_widgets", state); findError(diags, "Unable to delete widgets") == nil {
+		t.Errorf("expected a widget that was not found when deleted to be an error, got:\n%s", describeDiagnostics(diags))
+	}
+}
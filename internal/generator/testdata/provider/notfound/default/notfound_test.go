@@ -0,0 +1,89 @@
+// Tests of the provider generated from test-fixtures/patch.yaml, which documents no not found
+// responses, so that the defaults apply
+package provider
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// newNotesAPI serves a note, which responds with the status once it is gone
+func newNotesAPI(t *testing.T) (*testAPI, func(status int)) {
+	var mu sync.Mutex
+	gone := 0
+
+	api := newTestAPI(t, func(w http.ResponseWriter, r *testRequest) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case gone != 0 && r.Path == "/notes/note-1":
+			respond(w, gone, nil)
+		case r.Method == http.MethodPost && r.Path == "/notes":
+			respond(w, http.StatusCreated, map[string]interface{}{"id": "note-1", "title": "example"})
+		case r.Method == http.MethodGet && r.Path == "/notes/note-1":
+			respond(w, http.StatusOK, map[string]interface{}{"id": "note-1", "title": "example"})
+		case r.Method == http.MethodDelete && r.Path == "/notes/note-1":
+			respond(w, http.StatusNoContent, nil)
+		default:
+			respond(w, http.StatusBadRequest, nil)
+		}
+	})
+
+	return api, func(status int) {
+		mu.Lock()
+		defer mu.Unlock()
+		gone = status
+	}
+}
+
+func createNote(t *testing.T, p *testProvider) *testState {
+	t.Helper()
+
+	state, diags := p.Apply("example_notes", nil, map[string]tftypes.Value{"title": str("example")})
+	requireNoErrors(t, nil, diags)
+	return state
+}
+
+func TestNotFoundRead(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusGone} {
+		api, gone := newNotesAPI(t)
+		p := newTestProvider(t, api, nil)
+		state := createNote(t, p)
+
+		gone(status)
+		state, diags := p.Read("example_notes", state)
+		requireNoErrors(t, nil, diags)
+		if state != nil {
+			t.Errorf("expected a note read with status %d to be removed from state", status)
+		}
+	}
+}
+
+func TestNotFoundReadError(t *testing.T) {
+	api, gone := newNotesAPI(t)
+	p := newTestProvider(t, api, nil)
+	state := createNote(t, p)
+
+	gone(http.StatusInternalServerError)
+	if _, diags := p.Read("example_notes", state); findError(diags, "Unable to read notes") == nil {
+		t.Errorf("expected a failed read to be an error, got:\n%s", describeDiagnostics(diags))
+	}
+}
+
+func TestNotFoundDelete(t *testing.T) {
+	api, gone := newNotesAPI(t)
+	p := newTestProvider(t, api, nil)
+	state := createNote(t, p)
+
+	gone(http.StatusNotFound)
+	requireNoErrors(t, nil, p.Destroy("example_notes", state))
+
+	gone(http.StatusGone)
+	if diags := p.Destroy("example_notes", state); findError(diags, "Unable to delete notes") == nil {
+		t.Errorf("expected a note that was gone when deleted to be an error, got:\n%s", describeDiagnostics(diags))
+	}
+}
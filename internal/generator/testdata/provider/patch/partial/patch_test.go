@@ -0,0 +1,121 @@
+// Tests of the provider generated from test-fixtures/patch.yaml, where notes are updated with
+// a JSON merge patch, and tags with a JSON patch
+package provider
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// newPatchAPI serves a note and a tag, and applies the patches they are updated with
+func newPatchAPI(t *testing.T) *testAPI {
+	var mu sync.Mutex
+	note := map[string]interface{}{"id": "note-1"}
+	tag := map[string]interface{}{"id": "tag-1"}
+
+	return newTestAPI(t, func(w http.ResponseWriter, r *testRequest) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.Path == "/notes":
+			for name, value := range r.JSON(t).(map[string]interface{}) {
+				note[name] = value
+			}
+			respond(w, http.StatusCreated, note)
+		case r.Method == http.MethodPatch && r.Path == "/notes/note-1":
+			for name, value := range r.JSON(t).(map[string]interface{}) {
+				if value == nil {
+					delete(note, name)
+				} else {
+					note[name] = value
+				}
+			}
+			respond(w, http.StatusOK, note)
+		case r.Method == http.MethodGet && r.Path == "/notes/note-1":
+			respond(w, http.StatusOK, note)
+		case r.Method == http.MethodPost && r.Path == "/tags":
+			for name, value := range r.JSON(t).(map[string]interface{}) {
+				tag[name] = value
+			}
+			respond(w, http.StatusCreated, tag)
+		case r.Method == http.MethodPatch && r.Path == "/tags/tag-1":
+			for _, op := range r.JSON(t).([]interface{}) {
+				op := op.(map[string]interface{})
+				name := strings.TrimPrefix(op["path"].(string), "/")
+				switch op["op"] {
+				case "add", "replace":
+					tag[name] = op["value"]
+				case "remove":
+					delete(tag, name)
+				}
+			}
+			respond(w, http.StatusOK, tag)
+		case r.Method == http.MethodGet && r.Path == "/tags/tag-1":
+			respond(w, http.StatusOK, tag)
+		default:
+			respond(w, http.StatusBadRequest, nil)
+		}
+	})
+}
+
+func TestMergePatch(t *testing.T) {
+	api := newPatchAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	state, diags := p.Apply("example_notes", nil, map[string]tftypes.Value{"title": str("example"), "body": str("text")})
+	requireNoErrors(t, nil, diags)
+	api.Requests()
+
+	state, diags = p.Apply("example_notes", state, map[string]tftypes.Value{"title": str("renamed")})
+	requireNoErrors(t, nil, diags)
+
+	// Notes are read again for their entity tag, which the API does not respond with
+	req := api.Requests()[0]
+	if contentType := req.Header.Get("Content-Type"); contentType != "application/merge-patch+json" {
+		t.Errorf("expected a merge patch, got %s", contentType)
+	}
+	expected := map[string]interface{}{"title": "renamed", "body": nil}
+	if patch := req.JSON(t); !reflect.DeepEqual(patch, expected) {
+		t.Errorf("expected only the changes to be sent, got %v", patch)
+	}
+
+	if title := state.String(t, "title"); title != "renamed" {
+		t.Errorf("expected the title to be updated, got %q", title)
+	}
+	if !state.Attribute(t, "body").IsNull() {
+		t.Errorf("expected the body to be removed, got %s", state.Attribute(t, "body"))
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	api := newPatchAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	state, diags := p.Apply("example_tags", nil, map[string]tftypes.Value{"name": str("example")})
+	requireNoErrors(t, nil, diags)
+	api.Requests()
+
+	state, diags = p.Apply("example_tags", state, map[string]tftypes.Value{"name": str("renamed")})
+	requireNoErrors(t, nil, diags)
+
+	req := api.Request(t)
+	if contentType := req.Header.Get("Content-Type"); contentType != "application/json-patch+json" {
+		t.Errorf("expected a JSON patch, got %s", contentType)
+	}
+	expected := []interface{}{
+		map[string]interface{}{"op": "replace", "path": "/name", "value": "renamed"},
+	}
+	if patch := req.JSON(t); !reflect.DeepEqual(patch, expected) {
+		t.Errorf("expected only the changes to be sent, got %v", patch)
+	}
+
+	if name := state.String(t, "name"); name != "renamed" {
+		t.Errorf("expected the name to be updated, got %q", name)
+	}
+}
@@ -0,0 +1,52 @@
+// Tests of the provider generated from test-fixtures/security.yaml, where widgets are replaced
+// when they are updated
+package provider
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestReplacement(t *testing.T) {
+	var mu sync.Mutex
+	widget := map[string]interface{}{"id": "widget-1"}
+
+	api := newTestAPI(t, func(w http.ResponseWriter, r *testRequest) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.Path == "/widgets",
+			r.Method == http.MethodPut && r.Path == "/widgets/widget-1":
+			widget = r.JSON(t).(map[string]interface{})
+			widget["id"] = "widget-1"
+			respond(w, http.StatusOK, widget)
+		case r.Method == http.MethodGet && r.Path == "/widgets/widget-1":
+			respond(w, http.StatusOK, widget)
+		default:
+			respond(w, http.StatusBadRequest, nil)
+		}
+	})
+	p := newTestProvider(t, api, nil)
+
+	state, diags := p.Apply("example_widgets", nil, map[string]tftypes.Value{"name": str("example")})
+	requireNoErrors(t, nil, diags)
+	api.Requests()
+
+	state, diags = p.Apply("example_widgets", state, map[string]tftypes.Value{"name": str("renamed")})
+	requireNoErrors(t, nil, diags)
+
+	req := api.Request(t)
+	if req.Method != http.MethodPut {
+		t.Errorf("expected the widget to be replaced, got %s", req.Method)
+	}
+	if body := req.JSON(t).(map[string]interface{}); body["name"] != "renamed" {
+		t.Errorf("expected the widget to be sent, got %v", body)
+	}
+	if name := state.String(t, "name"); name != "renamed" {
+		t.Errorf("expected the name to be updated, got %q", name)
+	}
+}
@@ -0,0 +1,449 @@
+// Tests shared by the generated providers of the fixtures. This file is not templated: it is
+// copied into each generated provider package, along with the tests of the fixture, and
+// compiled with it. The provider is called over the plugin protocol, the way terraform calls
+// it, and its resources call an API served by the test.
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testRequest is a request received by the test API
+type testRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// JSON decodes the request body
+func (r *testRequest) JSON(t *testing.T) interface{} {
+	t.Helper()
+
+	var result interface{}
+	if err := json.Unmarshal(r.Body, &result); err != nil {
+		t.Fatalf("expected %s %s to have a JSON body, got %q: %s", r.Method, r.Path, r.Body, err)
+	}
+	return result
+}
+
+// testAPI is an API server that records the requests it receives, and responds to them with
+// the handler of the test
+type testAPI struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []*testRequest
+}
+
+func newTestAPI(t *testing.T, handler func(w http.ResponseWriter, r *testRequest)) *testAPI {
+	t.Helper()
+
+	api := &testAPI{}
+	api.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := &testRequest{Method: r.Method, Path: r.URL.RequestURI(), Header: r.Header, Body: body}
+
+		api.mu.Lock()
+		api.requests = append(api.requests, req)
+		api.mu.Unlock()
+
+		handler(w, req)
+	}))
+	t.Cleanup(api.Close)
+	return api
+}
+
+// Requests returns the requests received since the last call, and forgets them
+func (a *testAPI) Requests() []*testRequest {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	result := a.requests
+	a.requests = nil
+	return result
+}
+
+// Request returns the only request received since the last call, failing the test if there
+// is not exactly one
+func (a *testAPI) Request(t *testing.T) *testRequest {
+	t.Helper()
+
+	requests := a.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("expected one request, got %s", describeRequests(requests))
+	}
+	return requests[0]
+}
+
+func describeRequests(requests []*testRequest) string {
+	result := make([]string, 0, len(requests))
+	for _, r := range requests {
+		result = append(result, r.Method+" "+r.Path)
+	}
+	return fmt.Sprintf("%d: [%s]", len(requests), strings.Join(result, ", "))
+}
+
+// respond writes the value as a JSON response body with the status code
+func respond(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if value != nil {
+		json.NewEncoder(w).Encode(value)
+	}
+}
+
+// testState is the state of a resource, as terraform stores it
+type testState struct {
+	Value   tftypes.Value
+	Private []byte
+}
+
+// Attribute returns the value of a top level attribute
+func (s *testState) Attribute(t *testing.T, name string) tftypes.Value {
+	t.Helper()
+
+	var attributes map[string]tftypes.Value
+	if err := s.Value.As(&attributes); err != nil {
+		t.Fatal(err)
+	}
+	value, ok := attributes[name]
+	if !ok {
+		t.Fatalf("expected attribute %s in state %s", name, s.Value)
+	}
+	return value
+}
+
+// String returns the value of a top level string attribute, or empty if it is null
+func (s *testState) String(t *testing.T, name string) string {
+	t.Helper()
+
+	var result string
+	if value := s.Attribute(t, name); !value.IsNull() {
+		if err := value.As(&result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return result
+}
+
+// testProvider calls the generated provider over the plugin protocol
+type testProvider struct {
+	t       *testing.T
+	server  tfprotov6.ProviderServer
+	schemas map[string]*tfprotov6.Schema
+}
+
+// newTestProvider configures the provider with the attributes, and the endpoint of the API
+func newTestProvider(t *testing.T, api *testAPI, attributes map[string]tftypes.Value) *testProvider {
+	t.Helper()
+
+	ctx := context.Background()
+	server := providerserver.NewProtocol6(New("test")())()
+
+	schemaResp, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	requireNoErrors(t, err, schemaResp.Diagnostics)
+
+	config := map[string]tftypes.Value{"endpoint": tftypes.NewValue(tftypes.String, api.URL)}
+	for name, value := range attributes {
+		config[name] = value
+	}
+
+	configResp, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		Config: dynamicValue(t, object(t, schemaResp.Provider.ValueType(), config)),
+	})
+	requireNoErrors(t, err, configResp.Diagnostics)
+
+	return &testProvider{t: t, server: server, schemas: schemaResp.ResourceSchemas}
+}
+
+func (p *testProvider) schema(typeName string) *tfprotov6.Schema {
+	p.t.Helper()
+
+	schema, ok := p.schemas[typeName]
+	if !ok {
+		p.t.Fatalf("expected resource %s", typeName)
+	}
+	return schema
+}
+
+// Apply plans the change from the prior state to the configuration and applies it. A nil prior
+// state creates the resource. Like terraform, the test fails if the new state does not conform
+// to the plan. The new state is nil if the apply failed.
+func (p *testProvider) Apply(typeName string, prior *testState, attributes map[string]tftypes.Value) (*testState, []*tfprotov6.Diagnostic) {
+	p.t.Helper()
+
+	ctx := context.Background()
+	schema := p.schema(typeName)
+	typ := schema.ValueType()
+
+	config := object(p.t, typ, attributes)
+	priorValue := tftypes.NewValue(typ, nil)
+	var priorPrivate []byte
+	if prior != nil {
+		priorValue, priorPrivate = prior.Value, prior.Private
+	}
+
+	planResp, err := p.server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       dynamicValue(p.t, priorValue),
+		ProposedNewState: dynamicValue(p.t, proposedNewState(p.t, schema, priorValue, config)),
+		Config:           dynamicValue(p.t, config),
+		PriorPrivate:     priorPrivate,
+	})
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	if hasErrors(planResp.Diagnostics) {
+		return nil, planResp.Diagnostics
+	}
+	if len(planResp.RequiresReplace) > 0 {
+		p.t.Fatalf("expected %s to be updated in place, but %v require replacement", typeName, planResp.RequiresReplace)
+	}
+
+	planned, err := planResp.PlannedState.Unmarshal(typ)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+
+	applyResp, err := p.server.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:       typeName,
+		PriorState:     dynamicValue(p.t, priorValue),
+		PlannedState:   planResp.PlannedState,
+		Config:         dynamicValue(p.t, config),
+		PlannedPrivate: planResp.PlannedPrivate,
+	})
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	if hasErrors(applyResp.Diagnostics) {
+		return nil, applyResp.Diagnostics
+	}
+
+	value, err := applyResp.NewState.Unmarshal(typ)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	if !value.IsFullyKnown() {
+		p.t.Errorf("provider produced unknown values after apply: %s", value)
+	}
+	for _, problem := range conform(tftypes.NewAttributePath(), planned, value) {
+		p.t.Errorf("provider produced inconsistent result after apply: %s", problem)
+	}
+
+	return &testState{Value: value, Private: applyResp.Private}, applyResp.Diagnostics
+}
+
+// Read refreshes the state. The new state is nil if the resource was removed from state.
+func (p *testProvider) Read(typeName string, state *testState) (*testState, []*tfprotov6.Diagnostic) {
+	p.t.Helper()
+
+	typ := p.schema(typeName).ValueType()
+	resp, err := p.server.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+		TypeName:     typeName,
+		CurrentState: dynamicValue(p.t, state.Value),
+		Private:      state.Private,
+	})
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	if hasErrors(resp.Diagnostics) {
+		return state, resp.Diagnostics
+	}
+
+	value, err := resp.NewState.Unmarshal(typ)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	if value.IsNull() {
+		return nil, resp.Diagnostics
+	}
+	return &testState{Value: value, Private: resp.Private}, resp.Diagnostics
+}
+
+// Destroy applies the deletion of the resource
+func (p *testProvider) Destroy(typeName string, state *testState) []*tfprotov6.Diagnostic {
+	p.t.Helper()
+
+	typ := p.schema(typeName).ValueType()
+	resp, err := p.server.ApplyResourceChange(context.Background(), &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:       typeName,
+		PriorState:     dynamicValue(p.t, state.Value),
+		PlannedState:   dynamicValue(p.t, tftypes.NewValue(typ, nil)),
+		Config:         dynamicValue(p.t, tftypes.NewValue(typ, nil)),
+		PlannedPrivate: state.Private,
+	})
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	return resp.Diagnostics
+}
+
+// proposedNewState merges the configuration with the prior state the way terraform does: the
+// prior value of a computed attribute is proposed when the configuration leaves it null
+func proposedNewState(t *testing.T, schema *tfprotov6.Schema, prior, config tftypes.Value) tftypes.Value {
+	t.Helper()
+
+	if prior.IsNull() {
+		return config
+	}
+
+	var priorAttributes, configAttributes map[string]tftypes.Value
+	if err := prior.As(&priorAttributes); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.As(&configAttributes); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, att := range schema.Block.Attributes {
+		if att.Computed && configAttributes[att.Name].IsNull() {
+			configAttributes[att.Name] = priorAttributes[att.Name]
+		}
+	}
+	return tftypes.NewValue(config.Type(), configAttributes)
+}
+
+// conform lists the differences between the known values of the plan and the new state
+func conform(path *tftypes.AttributePath, planned, actual tftypes.Value) []string {
+	if !planned.IsKnown() {
+		return nil
+	}
+	if planned.IsNull() || actual.IsNull() {
+		if planned.IsNull() != actual.IsNull() {
+			return []string{fmt.Sprintf("%s: was %s, but now %s", path, planned, actual)}
+		}
+		return nil
+	}
+
+	switch planned.Type().(type) {
+	case tftypes.Object:
+		var plannedAttributes, actualAttributes map[string]tftypes.Value
+		if planned.As(&plannedAttributes) != nil || actual.As(&actualAttributes) != nil {
+			return []string{fmt.Sprintf("%s: could not compare %s with %s", path, planned, actual)}
+		}
+
+		var result []string
+		for name, value := range plannedAttributes {
+			result = append(result, conform(path.WithAttributeName(name), value, actualAttributes[name])...)
+		}
+		return result
+	case tftypes.List, tftypes.Set:
+		var plannedElements, actualElements []tftypes.Value
+		if planned.As(&plannedElements) != nil || actual.As(&actualElements) != nil || len(plannedElements) != len(actualElements) {
+			return []string{fmt.Sprintf("%s: was %s, but now %s", path, planned, actual)}
+		}
+
+		var result []string
+		for i := range plannedElements {
+			result = append(result, conform(path.WithElementKeyInt(i), plannedElements[i], actualElements[i])...)
+		}
+		return result
+	}
+
+	if !planned.Equal(actual) {
+		return []string{fmt.Sprintf("%s: was %s, but now %s", path, planned, actual)}
+	}
+	return nil
+}
+
+// object returns a value of the object type with the attributes. Attributes that are not
+// specified are null.
+func object(t *testing.T, typ tftypes.Type, attributes map[string]tftypes.Value) tftypes.Value {
+	t.Helper()
+
+	objectType, ok := typ.(tftypes.Object)
+	if !ok {
+		t.Fatalf("expected an object type, got %s", typ)
+	}
+
+	values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for name, value := range attributes {
+		if _, ok := objectType.AttributeTypes[name]; !ok {
+			t.Fatalf("unexpected attribute %s", name)
+		}
+		values[name] = value
+	}
+	return tftypes.NewValue(typ, values)
+}
+
+func dynamicValue(t *testing.T, value tftypes.Value) *tfprotov6.DynamicValue {
+	t.Helper()
+
+	result, err := tfprotov6.NewDynamicValue(value.Type(), value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &result
+}
+
+// str, num and strs are shorthands for attribute values
+func str(value string) tftypes.Value {
+	return tftypes.NewValue(tftypes.String, value)
+}
+
+func num(value int64) tftypes.Value {
+	return tftypes.NewValue(tftypes.Number, big.NewFloat(float64(value)))
+}
+
+func strs(values ...string) tftypes.Value {
+	elements := make([]tftypes.Value, 0, len(values))
+	for _, value := range values {
+		elements = append(elements, str(value))
+	}
+	return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, elements)
+}
+
+func hasErrors(diags []*tfprotov6.Diagnostic) bool {
+	for _, diag := range diags {
+		if diag.Severity == tfprotov6.DiagnosticSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// findError returns the first error diagnostic with the text in its summary or detail, or nil
+func findError(diags []*tfprotov6.Diagnostic, text string) *tfprotov6.Diagnostic {
+	for _, diag := range diags {
+		if diag.Severity == tfprotov6.DiagnosticSeverityError && strings.Contains(diag.Summary+"\n"+diag.Detail, text) {
+			return diag
+		}
+	}
+	return nil
+}
+
+func describeDiagnostics(diags []*tfprotov6.Diagnostic) string {
+	result := make([]string, 0, len(diags))
+	for _, diag := range diags {
+		result = append(result, fmt.Sprintf("%s: %s", diag.Summary, diag.Detail))
+	}
+	return strings.Join(result, "\n")
+}
+
+func requireNoErrors(t *testing.T, err error, diags []*tfprotov6.Diagnostic) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasErrors(diags) {
+		t.Fatalf("expected no errors, got:\n%s", describeDiagnostics(diags))
+	}
+}
@@ -28,6 +28,39 @@ func FindPrefix(words []string) string {
 	return test
 }
 
+// FindPathPrefix returns the leading path segments that all URL paths have in common, including
+// the trailing separator, or empty string ("") if there are none. Unlike FindPrefix, a partial
+// segment is never considered common, so "/v3/boards" and "/v3/boxes" share "/v3/" rather
+// than "/v3/bo". The last segment of each path is never part of the prefix.
+func FindPathPrefix(paths []string) string {
+	if len(paths) <= 1 {
+		return ""
+	}
+
+	var common []string
+	for index, path := range paths {
+		segments := strings.Split(path, "/")
+		// Only the directory portion of each path can be shared
+		segments = segments[:len(segments)-1]
+
+		if index == 0 {
+			common = segments
+			continue
+		}
+
+		length := 0
+		for length < len(common) && length < len(segments) && common[length] == segments[length] {
+			length++
+		}
+		common = common[:length]
+	}
+
+	if len(common) == 0 {
+		return ""
+	}
+	return strings.Join(common, "/") + "/"
+}
+
 // ToTitleName converts snake_case or kebab-case to TitleCase
 func ToTitleName(s string) string {
 	sb := strings.Builder{}
@@ -81,7 +114,9 @@ func ToHCLName(s string) string {
 		isUpper := unicode.IsUpper(c)
 
 		if index > 0 && (isUpper || (!isDigit && !isLetter)) {
-			if !preventUnderscore {
+			// Separators always separate words, even after consecutive capitals like "X-Token"
+			separator := !isDigit && !isLetter && sb.Len() > 0 && !strings.HasSuffix(sb.String(), "_")
+			if !preventUnderscore || separator {
 				sb.WriteByte('_')
 				preventUnderscore = true
 			}
@@ -36,6 +36,22 @@ func Test_FindPrefix(t *testing.T) {
 	}
 }
 
+func Test_FindPathPrefix(t *testing.T) {
+	cases := map[string]string{
+		"/v3/":        FindPathPrefix([]string{"/v3/boards", "/v3/boxes", "/v3/boards/{boardId}"}),
+		"/":           FindPathPrefix([]string{"/quota", "/quotas", "/quota/{specName}"}),
+		"/v1/orgs/":   FindPathPrefix([]string{"/v1/orgs/{org}", "/v1/orgs/{org}/teams"}),
+		"":            FindPathPrefix([]string{"/v1/boards"}),
+		"/api/{org}/": FindPathPrefix([]string{"/api/{org}/users", "/api/{org}/teams/{id}"}),
+	}
+
+	for expected, actual := range cases {
+		if expected != actual {
+			t.Errorf("expected %s but got %s", expected, actual)
+		}
+	}
+}
+
 func Test_ToHCLName(t *testing.T) {
 	cases := map[string]string{
 		"ThisIsCamelCase":    "this_is_camel_case",
@@ -45,6 +61,7 @@ func Test_ToHCLName(t *testing.T) {
 		"ELSTUPIDO":          "elstupido",
 		"APIHowdy":           "api_howdy",
 		"kebab-phrase-2":     "kebab_phrase_2",
+		"X-Nomad-Token":      "x_nomad_token",
 	}
 
 	for before, expected := range cases {
@@ -0,0 +1,43 @@
+package restutils
+
+import (
+	"net/http"
+	"strings"
+)
+
+// AsyncStatusHeaders are the response headers that commonly hold the URL of the status of an
+// asynchronous operation, in order of preference
+var AsyncStatusHeaders = []string{"Operation-Location", "Azure-AsyncOperation", "Location", "Content-Location"}
+
+// RESTAsync describes an operation that accepts requests with 202 Accepted and completes
+// them later
+type RESTAsync struct {
+	// Header is the documented response header with the URL of the operation status, or
+	// empty if none is documented
+	Header string
+}
+
+// ProbeForAsync determines whether the operation bound to the action is asynchronous, which is
+// the case if it documents a 202 Accepted response. The result is nil if it is not.
+func (s *RESTResource) ProbeForAsync(action *RESTAction) *RESTAsync {
+	op := s.GetOperation(action)
+	if op == nil {
+		return nil
+	}
+
+	response := op.Responses.Get(http.StatusAccepted)
+	if response == nil || response.Value == nil {
+		return nil
+	}
+
+	result := &RESTAsync{}
+	for _, header := range AsyncStatusHeaders {
+		for name := range response.Value.Headers {
+			if strings.EqualFold(name, header) {
+				result.Header = header
+				return result
+			}
+		}
+	}
+	return result
+}
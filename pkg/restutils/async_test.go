@@ -0,0 +1,36 @@
+package restutils
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func Test_ProbeForAsync(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/async.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s", err)
+	}
+
+	resource := &RESTResource{
+		probe:      &RESTProbe{Document: doc},
+		Name:       "Clusters",
+		RESTCreate: &RESTAction{Create, http.MethodPost, "/clusters"},
+		RESTShow:   &RESTAction{Show, http.MethodGet, "/clusters/{clusterId}"},
+		RESTUpdate: &RESTAction{Update, http.MethodPut, "/clusters/{clusterId}"},
+		RESTDelete: &RESTAction{Delete, http.MethodDelete, "/clusters/{clusterId}"},
+	}
+
+	if async := resource.ProbeForAsync(resource.RESTCreate); async == nil || async.Header != "Operation-Location" {
+		t.Errorf("expected create to be asynchronous with the Operation-Location header, got %#v", async)
+	}
+
+	if async := resource.ProbeForAsync(resource.RESTDelete); async == nil || async.Header != "" {
+		t.Errorf("expected delete to be asynchronous without a status header, got %#v", async)
+	}
+
+	if async := resource.ProbeForAsync(resource.RESTUpdate); async != nil {
+		t.Errorf("expected update to be synchronous, got %#v", async)
+	}
+}
@@ -18,6 +18,12 @@ const (
 	InHeader  In = "header"
 )
 
+// ParameterBinding selects an operation parameter by location and name
+type ParameterBinding struct {
+	In   In
+	Name string
+}
+
 type ActionBinding struct {
 	Path   string
 	Method string
@@ -38,6 +44,16 @@ type RESTBinding struct {
 	DeleteAction        *ActionBinding
 	IndexAction         *ActionBinding
 	CompositeAttributes []*AttributeBinding
+
+	// Identity is the name of the response attribute that identifies the resource,
+	// supplying the last path parameter of the read action. Optional.
+	Identity string
+
+	// Parameters are the query, header, or cookie parameters to expose as attributes
+	Parameters []ParameterBinding
+
+	// Envelope is a JSON pointer to the resource within request and response bodies. Optional.
+	Envelope string
 }
 
 func (p *RESTProbe) BindResources(bindings []RESTBinding) (map[string]*RESTResource, error) {
@@ -78,7 +94,7 @@ func (p *RESTProbe) BindResources(bindings []RESTBinding) (map[string]*RESTResou
 			}
 		}
 
-		result[binding.Name] = &RESTResource{
+		resource := &RESTResource{
 			probe:      p,
 			Name:       binding.Name,
 			RESTCreate: createOp,
@@ -86,7 +102,22 @@ func (p *RESTProbe) BindResources(bindings []RESTBinding) (map[string]*RESTResou
 			RESTUpdate: updateOp,
 			RESTDelete: deleteOp,
 			RESTIndex:  listOp,
+			Parameters: binding.Parameters,
+			Envelope:   binding.Envelope,
 		}
+
+		if binding.Identity != "" {
+			parameter, ok := resource.identityParameter()
+			if !ok {
+				return nil, fmt.Errorf("cannot bind identity of %s: read action has no path parameters", binding.Name)
+			}
+			resource.Identity = &RESTIdentity{
+				Parameter: parameter,
+				Attribute: binding.Identity,
+			}
+		}
+
+		result[binding.Name] = resource
 	}
 
 	return result, nil
@@ -104,6 +135,14 @@ func bindOperation(doc *openapi3.T, binding *ActionBinding, action RESTPseudonym
 		return nil, fmt.Errorf("cannot bind %s to %s: operation not found", action, binding.Path)
 	}
 
+	// Every path template parameter must be declared by the operation or the path item
+	parameters := mergeParameters(pathItem, operation)
+	for _, name := range PathParameters(binding.Path) {
+		if parameters.GetByInAndName(string(InPath), name) == nil {
+			return nil, fmt.Errorf("cannot bind %s to %s: path parameter \"%s\" is not declared", action, binding.Path, name)
+		}
+	}
+
 	return &RESTAction{
 		Name:   action,
 		Method: binding.Method,
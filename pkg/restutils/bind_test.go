@@ -80,4 +80,36 @@ func Test_BindResources(t *testing.T) {
 			t.Errorf("invalid binding: %s", err)
 		}
 	})
+	t.Run("Cannot bind undeclared path parameters", func(t *testing.T) {
+		doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: Undeclared
+  version: "1"
+paths:
+  /boards/{boardId}:
+    get:
+      responses:
+        "200":
+          description: Success
+`))
+		if err != nil {
+			t.Fatalf("invalid openapi fixture: %s", err)
+		}
+
+		probe := NewProbe(doc)
+		_, err = probe.BindResources([]RESTBinding{
+			{
+				Name: "Boards",
+				ReadAction: &ActionBinding{
+					Path:   "/boards/{boardId}",
+					Method: "GET",
+				},
+			},
+		})
+
+		if err == nil {
+			t.Error("expected an undeclared path parameter error")
+		}
+	})
 }
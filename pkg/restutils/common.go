@@ -0,0 +1,91 @@
+package restutils
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// CommonParameterThreshold is the fraction of operations that must declare a parameter for it
+// to be considered common to the API
+const CommonParameterThreshold = 0.5
+
+var commonParameterActions = []RESTPseudonym{Create, Show, Index, Update, Delete}
+
+// ProbeForCommonParameters finds the query, header, and cookie parameters that are declared by
+// more than the threshold fraction of the operations bound to the resources. Parameters like
+// these, such as a region or tenant, are better configured once for the provider than on every
+// resource. Reads and writes are counted separately and a parameter must be common to both, so
+// that pagination or blocking query parameters are not mistaken for common ones. Header names
+// are compared case insensitively.
+func ProbeForCommonParameters(resources []*RESTResource, threshold float64) []*Attribute {
+	counts := map[bool]map[string]int{true: {}, false: {}}
+	totals := map[bool]int{true: 0, false: 0}
+	found := make(map[string]*Attribute)
+	seen := make(map[string]bool)
+
+	for _, resource := range resources {
+		for _, name := range commonParameterActions {
+			action := resource.action(name)
+			if action == nil || seen[action.Method+" "+action.Path] {
+				continue
+			}
+			seen[action.Method+" "+action.Path] = true
+
+			read := strings.EqualFold(action.Method, http.MethodGet) || strings.EqualFold(action.Method, http.MethodHead)
+			totals[read]++
+
+			for _, paramRef := range resource.GetParameters(action) {
+				parameter := paramRef.Value
+				if parameter.In == string(InPath) {
+					continue
+				}
+
+				key := parameter.In + " " + parameter.Name
+				if In(parameter.In) == InHeader {
+					key = parameter.In + " " + strings.ToLower(parameter.Name)
+				}
+				counts[read][key]++
+
+				if _, ok := found[key]; !ok {
+					att := &Attribute{
+						Name:        parameter.Name,
+						In:          In(parameter.In),
+						Description: parameter.Description,
+						Type:        TypeString,
+					}
+					if parameter.Schema != nil && parameter.Schema.Value != nil {
+						att.Type = OASTypeFromString(parameter.Schema.Value.Type)
+						att.Format = OASFormatFromString(parameter.Schema.Value.Format)
+						att.Schema = parameter.Schema.Value
+					}
+					found[key] = att
+				}
+			}
+		}
+	}
+
+	result := make([]*Attribute, 0)
+	for key := range found {
+		common := true
+		for _, read := range []bool{true, false} {
+			if totals[read] > 0 && float64(counts[read][key]) <= threshold*float64(totals[read]) {
+				common = false
+			}
+		}
+
+		if common {
+			log.Printf("[DEBUG] Found common parameter %s in %d reads and %d writes", key, counts[true][key], counts[false][key])
+			result = append(result, found[key])
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].In != result[j].In {
+			return result[i].In < result[j].In
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
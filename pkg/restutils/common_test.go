@@ -0,0 +1,67 @@
+package restutils
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func Test_ProbeForCommonParameters(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/openapi3/nomad.yaml")
+	if err != nil {
+		t.Fatalf("could not load nomad.yaml: %v", err)
+	}
+
+	probe := NewProbe(doc)
+	resources := make([]*RESTResource, 0)
+	for _, resource := range probe.ProbeForResources() {
+		resources = append(resources, resource)
+	}
+
+	t.Run("nomad", func(t *testing.T) {
+		common := ProbeForCommonParameters(resources, CommonParameterThreshold)
+
+		expected := map[string]In{
+			"X-Nomad-Token": InHeader,
+			"namespace":     InQuery,
+			"region":        InQuery,
+		}
+
+		for name, in := range expected {
+			var found *Attribute
+			for _, att := range common {
+				if att.Name == name {
+					found = att
+				}
+			}
+
+			if found == nil {
+				t.Errorf("expected common parameter %s, got %v", name, common)
+				continue
+			}
+
+			if found.In != in {
+				t.Errorf("parameter %s In expected %s, actual %s", name, in, found.In)
+			}
+		}
+
+		// Blocking query parameters are only declared by reads
+		for _, att := range common {
+			if att.Name == "index" || att.Name == "wait" {
+				t.Errorf("expected read parameter %s to be excluded", att.Name)
+			}
+		}
+	})
+
+	t.Run("threshold", func(t *testing.T) {
+		if common := ProbeForCommonParameters(resources, 1); len(common) != 0 {
+			t.Errorf("expected no parameters to be declared by every operation, got %v", common)
+		}
+	})
+
+	t.Run("no resources", func(t *testing.T) {
+		if common := ProbeForCommonParameters(nil, CommonParameterThreshold); len(common) != 0 {
+			t.Errorf("expected no common parameters, got %v", common)
+		}
+	})
+}
@@ -37,9 +37,9 @@ func compositeAttributes(s *RESTResource, mediaType string) []*Attribute {
 		op := s.GetOperation(s.RESTShow)
 		if op != nil {
 			log.Print("[DEBUG] Extracting parameter attributes from show action")
-			extractParameterAttributes(attMap, Show, op)
+			extractParameterAttributes(attMap, Show, s.GetParameters(s.RESTShow), s.Parameters)
 			log.Print("[DEBUG] Extracting response body attributes from show action")
-			extractResponseAttributes(attMap, Show, mediaType, op)
+			extractResponseAttributes(attMap, Show, mediaType, s.Envelope, op)
 		} else {
 			log.Print("[WARN] No show operation found")
 		}
@@ -51,9 +51,10 @@ func compositeAttributes(s *RESTResource, mediaType string) []*Attribute {
 		op := s.GetOperation(s.RESTCreate)
 		if op != nil {
 			log.Print("[DEBUG] Extracting parameter attributes from create action")
-			extractParameterAttributes(attMap, Create, s.GetOperation(s.RESTCreate))
+			extractParameterAttributes(attMap, Create, s.GetParameters(s.RESTCreate), s.Parameters)
 			log.Print("[DEBUG] Extracting request body attributes from create action")
-			extractRequestAttributes(attMap, Create, mediaType, s.GetOperation(s.RESTCreate))
+			requestMediaType := s.ProbeForRequestMediaType(s.RESTCreate, mediaType)
+			extractRequestAttributes(attMap, Create, requestMediaType, s.requestEnvelope(requestMediaType), op)
 		} else {
 			log.Print("[WARN] No create operation found")
 		}
@@ -61,17 +62,35 @@ func compositeAttributes(s *RESTResource, mediaType string) []*Attribute {
 
 	// The request body attributes from the update action are also supported
 	if s.RESTUpdate != nil {
-		op := s.GetOperation(s.RESTCreate)
+		op := s.GetOperation(s.RESTUpdate)
 		if op != nil {
 			log.Print("[DEBUG] Extracting parameter attributes from update action")
-			extractParameterAttributes(attMap, Update, s.GetOperation(s.RESTUpdate))
+			extractParameterAttributes(attMap, Update, s.GetParameters(s.RESTUpdate), s.Parameters)
 			log.Print("[DEBUG] Extracting request body attributes from update action")
-			extractRequestAttributes(attMap, Update, mediaType, s.GetOperation(s.RESTUpdate))
+			requestMediaType := s.ProbeForRequestMediaType(s.RESTUpdate, mediaType)
+			extractRequestAttributes(attMap, Update, requestMediaType, s.requestEnvelope(requestMediaType), op)
 		} else {
 			log.Print("[WARN] No update operation found")
 		}
 	}
 
+	// Only parameters are extracted from the delete action, since it has no body
+	if s.RESTDelete != nil {
+		log.Print("[DEBUG] Extracting parameter attributes from delete action")
+		extractParameterAttributes(attMap, Delete, s.GetParameters(s.RESTDelete), s.Parameters)
+	}
+
+	// The identity path parameter is redundant when its value is supplied
+	// by another attribute.
+	if s.Identity != nil {
+		if att, ok := attMap[s.Identity.Attribute]; ok {
+			att.Identity = true
+			if s.Identity.Parameter != s.Identity.Attribute {
+				delete(attMap, s.Identity.Parameter)
+			}
+		}
+	}
+
 	return attributeValues(attMap)
 }
 
@@ -90,37 +109,54 @@ func attributeValues(attMap map[string]*Attribute) []*Attribute {
 
 // extractParameterAttributes extracts at all openapi operation parameters that are found
 // in the path. Other parameters are usually uninteresting and exhaustive for the purposes
-// or resource probing.
-func extractParameterAttributes(attMap map[string]*Attribute, action RESTPseudonym, op *openapi3.Operation) {
-	for _, paramRef := range op.Parameters {
+// or resource probing, so they are only extracted if they are included explicitly. The
+// parameters should include those declared on the path item.
+func extractParameterAttributes(attMap map[string]*Attribute, action RESTPseudonym, parameters openapi3.Parameters, include []ParameterBinding) {
+	for _, paramRef := range parameters {
 		parameter := paramRef.Value
-		if parameter.In == "path" {
+		if parameter.In == string(InPath) {
 			// Implicitly required because this is a path parameter
-			update(attMap, action, parameter.Name, false, true, parameter.Schema.Value)
+			update(attMap, action, parameter.Name, InPath, false, true, parameter.Schema.Value)
+		} else if includesParameter(include, In(parameter.In), parameter.Name) {
+			update(attMap, action, parameter.Name, In(parameter.In), false, parameter.Required, parameter.Schema.Value)
 		}
-		// Other types of parameters are not substantial: cookie, header, or query
 	}
 }
 
-// extractRequestAttributes recursively extracts attributes from the request body
-func extractRequestAttributes(attMap map[string]*Attribute, action RESTPseudonym, mediaType string, op *openapi3.Operation) {
+func includesParameter(include []ParameterBinding, in In, name string) bool {
+	for _, binding := range include {
+		if binding.In == in && SameParameterName(in, binding.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractRequestAttributes recursively extracts attributes from the resource within the
+// request body envelope, flattening JSON:API resource objects
+func extractRequestAttributes(attMap map[string]*Attribute, action RESTPseudonym, mediaType string, envelope string, op *openapi3.Operation) {
 	if op.RequestBody != nil {
 		body := op.RequestBody.Value.Content.Get(mediaType)
 		if body != nil {
-			extractFromSchemas(attMap, action, body.Schema.Value.Properties)
+			if schema := resourceSchema(body.Schema.Value, envelope); schema != nil {
+				extractFromSchemas(attMap, action, schema.Properties)
+			}
 		}
 	} else {
 		log.Printf("[DEBUG] Action %s has no request body of type %s", action, mediaType)
 	}
 }
 
-// extractRequestAttributes recursively extracts attributes from the response body
-func extractResponseAttributes(attMap map[string]*Attribute, action RESTPseudonym, mediaType string, op *openapi3.Operation) {
+// extractResponseAttributes recursively extracts attributes from the resource within the
+// response body envelope, flattening JSON:API resource objects
+func extractResponseAttributes(attMap map[string]*Attribute, action RESTPseudonym, mediaType string, envelope string, op *openapi3.Operation) {
 	for _, code := range successfulResponseCodes[action] {
 		if response := op.Responses.Get(code); response != nil {
 			body := response.Value.Content.Get(mediaType)
 			if body != nil {
-				extractFromSchemas(attMap, action, body.Schema.Value.Properties)
+				if schema := resourceSchema(body.Schema.Value, envelope); schema != nil {
+					extractFromSchemas(attMap, action, schema.Properties)
+				}
 				break
 			}
 		} else {
@@ -130,13 +166,15 @@ func extractResponseAttributes(attMap map[string]*Attribute, action RESTPseudony
 }
 
 // extractFromSchemas recursively extracts attributes from the specified OpenAPI schema,
-// using the specified action to determine the attribute properties.
+// using the specified action to determine the attribute properties. Properties the schema
+// declares readOnly are read-only even when they are in a request body, and are not required.
 func extractFromSchemas(attMap map[string]*Attribute, action RESTPseudonym, schemas openapi3.Schemas) {
 	for name, prop_ref := range schemas {
 		if action == Index || action == Show {
-			update(attMap, action, name, true, false, prop_ref.Value)
+			update(attMap, action, name, InContent, true, false, prop_ref.Value)
 		} else if action == Create || action == Update {
-			update(attMap, action, name, false, sliceIncludes(prop_ref.Value.Required, name), prop_ref.Value)
+			readonly := prop_ref.Value.ReadOnly
+			update(attMap, action, name, InContent, readonly, !readonly && sliceIncludes(prop_ref.Value.Required, name), prop_ref.Value)
 		}
 	}
 }
@@ -159,7 +197,7 @@ func formatForLog(format string) string {
 
 // update will create or update the specified attribute map from schema, recursively extracting
 // attributes from sub-schema.
-func update(attMap map[string]*Attribute, action RESTPseudonym, name string, readonly bool, required bool, schema *openapi3.Schema) {
+func update(attMap map[string]*Attribute, action RESTPseudonym, name string, in In, readonly bool, required bool, schema *openapi3.Schema) {
 	existing, ok := attMap[name]
 	if !ok {
 		// This is an attribute we've not seen before.
@@ -189,6 +227,7 @@ func update(attMap map[string]*Attribute, action RESTPseudonym, name string, rea
 
 		attMap[name] = &Attribute{
 			Name:        name,
+			In:          in,
 			ReadOnly:    readonly,
 			Type:        OASTypeFromString(schema.Type),
 			ElemType:    elemType,
@@ -203,7 +242,7 @@ func update(attMap map[string]*Attribute, action RESTPseudonym, name string, rea
 		// only need to be detected once per param to be set.
 		if !readonly && existing.ReadOnly {
 			log.Printf("[DEBUG] Param %s (%s) for %s is not read-only", name, schema.Type, action)
-			setReadonlyAll(existing, false)
+			setWritable(existing, schema)
 		}
 
 		if string(existing.Type) != schema.Type {
@@ -226,3 +265,24 @@ func setReadonlyAll(att *Attribute, value bool) {
 		}
 	}
 }
+
+// setWritable recursively clears the readonly property of an attribute that is set by
+// clients, except for the subattributes the request schema declares readOnly
+func setWritable(att *Attribute, schema *openapi3.Schema) {
+	att.ReadOnly = false
+
+	properties := schema.Properties
+	if isArray(schema) && schema.Items != nil && schema.Items.Value != nil {
+		properties = schema.Items.Value.Properties
+	}
+
+	for _, sub := range att.Attributes {
+		prop, ok := properties[sub.Name]
+		switch {
+		case !ok || prop.Value == nil:
+			setReadonlyAll(sub, false)
+		case !prop.Value.ReadOnly:
+			setWritable(sub, prop.Value)
+		}
+	}
+}
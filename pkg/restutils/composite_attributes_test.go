@@ -412,5 +412,100 @@ func Test_compositeAttributes(t *testing.T) {
 				}
 			}
 		})
+
+		t.Run("included parameters", func(t *testing.T) {
+			var resource = &RESTResource{
+				probe: &RESTProbe{
+					Document: doc,
+				},
+				Name:       "Quota",
+				RESTShow:   &RESTAction{Show, http.MethodGet, "/quota/{specName}"},
+				RESTCreate: &RESTAction{Create, http.MethodPost, "/quota"},
+				RESTUpdate: &RESTAction{Update, http.MethodPost, "/quota/{specName}"},
+				RESTDelete: &RESTAction{Delete, http.MethodDelete, "/quota/{specName}"},
+				Parameters: []ParameterBinding{
+					{In: InQuery, Name: "namespace"},
+					{In: InHeader, Name: "x-nomad-token"},
+				},
+			}
+
+			attributes := compositeAttributes(resource, "application/json")
+
+			expected := map[string]In{
+				"specName":      InPath,
+				"namespace":     InQuery,
+				"X-Nomad-Token": InHeader,
+				"Name":          InContent,
+			}
+
+			for name, in := range expected {
+				var found *Attribute = nil
+				for _, search := range attributes {
+					if search.Name == name {
+						found = search
+						break
+					}
+				}
+
+				if found == nil {
+					t.Errorf("Expected an attribute named %s in compositeAttributes", name)
+					continue
+				}
+
+				if found.In != in {
+					t.Errorf("attribute %s In expected %s, actual %s", name, in, found.In)
+				}
+			}
+
+			for _, att := range attributes {
+				if att.Name == "region" {
+					t.Errorf("Expected parameter region to be excluded")
+				}
+			}
+		})
+	})
+}
+
+func Test_compositeAttributesReadOnly(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/security.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s\n", err)
+	}
+
+	probe := NewProbe(doc)
+	widgets, ok := probe.ProbeForResources()["Widgets"]
+	if !ok {
+		t.Fatal("expected resources to contain \"Widgets\"")
+	}
+
+	// The request and response bodies share a schema that declares the id read-only
+	for _, att := range compositeAttributes(widgets, "application/json") {
+		if readOnly := att.Name == "id"; att.ReadOnly != readOnly || (readOnly && att.Required) {
+			t.Errorf("expected attribute %s read-only %t and not required, got read-only %t, required %t", att.Name, readOnly, att.ReadOnly, att.Required)
+		}
+	}
+
+	t.Run("nested", func(t *testing.T) {
+		attMap := make(map[string]*Attribute)
+		status := &openapi3.Schema{
+			Type: "object",
+			Properties: openapi3.Schemas{
+				"state":   {Value: &openapi3.Schema{Type: "string", ReadOnly: true}},
+				"enabled": {Value: &openapi3.Schema{Type: "boolean"}},
+			},
+		}
+		schemas := openapi3.Schemas{"status": {Value: status}}
+
+		extractFromSchemas(attMap, Show, schemas)
+		extractFromSchemas(attMap, Create, schemas)
+
+		if attMap["status"].ReadOnly {
+			t.Error("expected status to be writable")
+		}
+		for _, sub := range attMap["status"].Attributes {
+			if readOnly := sub.Name == "state"; sub.ReadOnly != readOnly {
+				t.Errorf("expected status attribute %s read-only %t", sub.Name, readOnly)
+			}
+		}
 	})
 }
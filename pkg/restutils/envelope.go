@@ -0,0 +1,77 @@
+package restutils
+
+import (
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// envelopeProperties are the properties that commonly accompany a "data" envelope property,
+// like pagination, hypermedia links, and the members of JSON:API documents
+var envelopeProperties = map[string]bool{"meta": true, "links": true, "_links": true, "included": true, "jsonapi": true}
+
+// pointerTokens returns the unescaped reference tokens of a JSON pointer, like ["spec", "name"]
+// for "/spec/name"
+func pointerTokens(pointer string) []string {
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens
+}
+
+// envelopeSchema returns the schema of the property located by the envelope JSON pointer, like
+// "/data", or the schema itself if the envelope is empty. The result is nil if there is no
+// such property.
+func envelopeSchema(schema *openapi3.Schema, envelope string) *openapi3.Schema {
+	if envelope == "" {
+		return schema
+	}
+
+	for _, token := range pointerTokens(envelope) {
+		if schema == nil {
+			return nil
+		}
+
+		property, ok := schema.Properties[token]
+		if !ok || property.Value == nil {
+			return nil
+		}
+		schema = property.Value
+	}
+	return schema
+}
+
+// ProbeForEnvelope determines whether the show response body wraps the resource in a "data"
+// property, which is the case if its other properties are metadata like "meta" and "links".
+// The result is a JSON pointer to the resource, or empty if the body is the resource.
+func (s *RESTResource) ProbeForEnvelope(mediaType string) string {
+	op := s.GetOperation(s.RESTShow)
+	if op == nil {
+		return ""
+	}
+
+	for _, code := range successfulResponseCodes[Show] {
+		response := op.Responses.Get(code)
+		if response == nil || response.Value == nil {
+			continue
+		}
+
+		body := response.Value.Content.Get(mediaType)
+		if body == nil || body.Schema == nil || body.Schema.Value == nil {
+			continue
+		}
+
+		data, ok := body.Schema.Value.Properties["data"]
+		if !ok || data.Value == nil || len(data.Value.Properties) == 0 {
+			return ""
+		}
+		for name := range body.Schema.Value.Properties {
+			if name != "data" && !envelopeProperties[name] {
+				return ""
+			}
+		}
+		return "/data"
+	}
+	return ""
+}
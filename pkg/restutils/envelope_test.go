@@ -0,0 +1,61 @@
+package restutils
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func Test_Envelope(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/envelope.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s", err)
+	}
+
+	probe := NewProbe(doc)
+	projects, ok := probe.ProbeForResources()["Projects"]
+	if !ok {
+		t.Fatal("expected \"Projects\" resource")
+	}
+
+	if envelope := projects.ProbeForEnvelope("application/json"); envelope != "/data" {
+		t.Fatalf("expected the /data envelope, got %q", envelope)
+	}
+
+	projects.Envelope = "/data"
+	if identity := projects.ProbeForIdentity("application/json"); identity == nil || identity.Attribute != "id" {
+		t.Errorf("expected the id attribute within the envelope to identify projects, got %#v", identity)
+	}
+
+	names := make(map[string]bool)
+	for _, att := range projects.ProbeForAttributes("application/json") {
+		names[att.Name] = true
+	}
+
+	for _, name := range []string{"id", "name", "description"} {
+		if !names[name] {
+			t.Errorf("expected attribute %s within the envelope, got %v", name, names)
+		}
+	}
+	if names["data"] || names["meta"] {
+		t.Errorf("expected the envelope properties to be excluded, got %v", names)
+	}
+
+	if envelopeSchema(doc.Components.Schemas["ProjectResponse"].Value, "/missing") != nil {
+		t.Error("expected no schema for a missing envelope property")
+	}
+}
+
+func Test_ProbeForEnvelope(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/restlike.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s", err)
+	}
+
+	probe := NewProbe(doc)
+	for name, resource := range probe.ProbeForResources() {
+		if envelope := resource.ProbeForEnvelope("application/json"); envelope != "" {
+			t.Errorf("expected %s to have no envelope, got %q", name, envelope)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package restutils
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Property names commonly used by error response bodies, in order of preference
+var (
+	errorMessageProperties = []string{"message", "detail", "error_description", "title"}
+	errorCodeProperties    = []string{"code", "type", "error"}
+	errorDetailsProperties = []string{"details", "errors", "violations", "invalid_params", "fieldErrors"}
+	errorFieldProperties   = []string{"field", "target", "path", "pointer", "param", "property", "name"}
+)
+
+// RESTErrorSchema locates the parts of an error response body, as JSON pointers. The message is
+// always located, while the others are empty if they were not found.
+type RESTErrorSchema struct {
+	Code    string
+	Message string
+
+	// Details locates an array of field errors. DetailField and DetailMessage locate the name
+	// of the rejected field and its message within each field error.
+	Details       string
+	DetailField   string
+	DetailMessage string
+}
+
+// isJSONMediaType reports whether a media type, like "application/problem+json", is JSON
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// findProperty returns the first of the names that is a property of the schema with one of
+// the types, or an empty string if none is
+func findProperty(schema *openapi3.Schema, names []string, types ...string) string {
+	for _, name := range names {
+		property, ok := schema.Properties[name]
+		if !ok || property.Value == nil {
+			continue
+		}
+		for _, t := range types {
+			if property.Value.Type == t {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// probeErrorSchema locates the parts of an error response body described by the schema. The
+// error may be wrapped by an "error" object, as in {"error": {"code": ..., "message": ...}}.
+func probeErrorSchema(schema *openapi3.Schema, prefix string) *RESTErrorSchema {
+	if schema == nil || (!isObject(schema) && len(schema.Properties) == 0) {
+		return nil
+	}
+
+	message := findProperty(schema, errorMessageProperties, "string")
+	if message == "" {
+		if wrapped, ok := schema.Properties["error"]; ok && wrapped.Value != nil {
+			return probeErrorSchema(wrapped.Value, prefix+"/error")
+		}
+		return nil
+	}
+
+	result := &RESTErrorSchema{Message: prefix + "/" + message}
+	if code := findProperty(schema, errorCodeProperties, "string", "integer"); code != "" && code != message {
+		result.Code = prefix + "/" + code
+	}
+
+	details := findProperty(schema, errorDetailsProperties, "array")
+	if details == "" {
+		return result
+	}
+
+	items := schema.Properties[details].Value.Items
+	if items == nil || items.Value == nil || len(items.Value.Properties) == 0 {
+		return result
+	}
+
+	field := findProperty(items.Value, errorFieldProperties, "string")
+	detailMessage := findProperty(items.Value, errorMessageProperties, "string")
+	if field != "" && detailMessage != "" {
+		result.Details = prefix + "/" + details
+		result.DetailField = "/" + field
+		result.DetailMessage = "/" + detailMessage
+	}
+	return result
+}
+
+// ProbeForErrorSchema locates the parts of the error response bodies documented by the
+// operation bound to the action. Client errors are preferred, since they describe the fields
+// that were rejected. The result is nil if no documented error has a JSON message.
+func (s *RESTResource) ProbeForErrorSchema(action *RESTAction) *RESTErrorSchema {
+	op := s.GetOperation(action)
+	if op == nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(op.Responses))
+	for key := range op.Responses {
+		if strings.HasPrefix(key, "4") || strings.HasPrefix(key, "5") || key == "default" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		response := op.Responses[key]
+		if response == nil || response.Value == nil {
+			continue
+		}
+
+		mediaTypes := make([]string, 0, len(response.Value.Content))
+		for mediaType := range response.Value.Content {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+		sort.Strings(mediaTypes)
+
+		for _, mediaType := range mediaTypes {
+			content := response.Value.Content[mediaType]
+			if !isJSONMediaType(mediaType) || content.Schema == nil {
+				continue
+			}
+			if result := probeErrorSchema(content.Schema.Value, ""); result != nil {
+				return result
+			}
+		}
+	}
+	return nil
+}
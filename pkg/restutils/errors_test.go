@@ -0,0 +1,45 @@
+package restutils
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func Test_ProbeForErrorSchema(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/security.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s", err)
+	}
+
+	resource := &RESTResource{
+		probe:      &RESTProbe{Document: doc},
+		Name:       "Widgets",
+		RESTShow:   &RESTAction{Show, http.MethodGet, "/widgets/{widgetId}"},
+		RESTUpdate: &RESTAction{Update, http.MethodPut, "/widgets/{widgetId}"},
+		RESTDelete: &RESTAction{Delete, http.MethodDelete, "/widgets/{widgetId}"},
+	}
+
+	cases := map[*RESTAction]*RESTErrorSchema{
+		resource.RESTShow: {Code: "/error/code", Message: "/error/message"},
+		resource.RESTUpdate: {
+			Code:          "/code",
+			Message:       "/message",
+			Details:       "/details",
+			DetailField:   "/field",
+			DetailMessage: "/message",
+		},
+	}
+
+	for action, expected := range cases {
+		actual := resource.ProbeForErrorSchema(action)
+		if actual == nil || *actual != *expected {
+			t.Errorf("expected %s %s to have error schema %#v, got %#v", action.Method, action.Path, expected, actual)
+		}
+	}
+
+	if actual := resource.ProbeForErrorSchema(resource.RESTDelete); actual != nil {
+		t.Errorf("expected delete to have no error schema, got %#v", actual)
+	}
+}
@@ -0,0 +1,50 @@
+package restutils
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ProbeForETag reports whether a successful response of the operation bound to the action
+// documents an ETag header, which identifies the version of the resource
+func (s *RESTResource) ProbeForETag(action *RESTAction) bool {
+	op := s.GetOperation(action)
+	if op == nil {
+		return false
+	}
+
+	for key, response := range op.Responses {
+		if !strings.HasPrefix(key, "2") || response == nil || response.Value == nil {
+			continue
+		}
+		for name := range response.Value.Headers {
+			if strings.EqualFold(name, "ETag") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ProbeForIfMatch reports whether the operation bound to the action is conditional on the
+// version of the resource, which is the case if it declares an If-Match header parameter or
+// documents a 412 Precondition Failed response
+func (s *RESTResource) ProbeForIfMatch(action *RESTAction) bool {
+	op := s.GetOperation(action)
+	if op == nil {
+		return false
+	}
+
+	if op.Responses.Get(http.StatusPreconditionFailed) != nil {
+		return true
+	}
+
+	for _, param := range s.GetParameters(action) {
+		if param.Value != nil && param.Value.In == openapi3.ParameterInHeader && strings.EqualFold(param.Value.Name, "If-Match") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package restutils
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func Test_ProbeForETag(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/patch.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s", err)
+	}
+
+	resource := &RESTResource{probe: &RESTProbe{Document: doc}}
+
+	if !resource.ProbeForETag(&RESTAction{Show, http.MethodGet, "/notes/{noteId}"}) {
+		t.Error("expected notes to document an ETag")
+	}
+
+	if resource.ProbeForETag(&RESTAction{Show, http.MethodGet, "/tags/{tagId}"}) {
+		t.Error("expected tags to document no ETag")
+	}
+
+	cases := map[*RESTAction]bool{
+		{Update, http.MethodPatch, "/notes/{noteId}"}:  true,
+		{Delete, http.MethodDelete, "/notes/{noteId}"}: true,
+		{Update, http.MethodPatch, "/tags/{tagId}"}:    false,
+	}
+
+	for action, expected := range cases {
+		if actual := resource.ProbeForIfMatch(action); actual != expected {
+			t.Errorf("expected %s %s to be conditional: %t, got %t", action.Method, action.Path, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package restutils
+
+import (
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// IdempotencyKeyHeaders are the request headers that commonly identify each call of an
+// operation, so that the API processes it once however often it is retried
+var IdempotencyKeyHeaders = []string{"Idempotency-Key", "X-Idempotency-Key"}
+
+// ProbeForIdempotencyKey returns the name of the idempotency key header declared by the
+// operation bound to the action, or an empty string if it declares none
+func (s *RESTResource) ProbeForIdempotencyKey(action *RESTAction) string {
+	for _, param := range s.GetParameters(action) {
+		if param.Value == nil || param.Value.In != openapi3.ParameterInHeader {
+			continue
+		}
+		for _, header := range IdempotencyKeyHeaders {
+			if strings.EqualFold(param.Value.Name, header) {
+				return param.Value.Name
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,25 @@
+package restutils
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func Test_ProbeForIdempotencyKey(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/patch.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s", err)
+	}
+
+	resource := &RESTResource{probe: &RESTProbe{Document: doc}}
+
+	if actual := resource.ProbeForIdempotencyKey(&RESTAction{Create, http.MethodPost, "/notes"}); actual != "Idempotency-Key" {
+		t.Errorf("expected notes to declare an Idempotency-Key header, got %q", actual)
+	}
+
+	if actual := resource.ProbeForIdempotencyKey(&RESTAction{Create, http.MethodPost, "/tags"}); actual != "" {
+		t.Errorf("expected tags to declare no idempotency key, got %q", actual)
+	}
+}
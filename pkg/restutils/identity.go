@@ -0,0 +1,106 @@
+package restutils
+
+import (
+	"log"
+	"strings"
+	"unicode"
+)
+
+// PathParameters returns the names of the parameters in a path template, in order
+func PathParameters(path string) []string {
+	result := make([]string, 0)
+	for _, part := range strings.Split(path, "/") {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			result = append(result, part[1:len(part)-1])
+		}
+	}
+	return result
+}
+
+// identityParameter returns the last path parameter of the show action, which
+// is conventionally the one that identifies the resource.
+func (s *RESTResource) identityParameter() (string, bool) {
+	if s.RESTShow == nil {
+		return "", false
+	}
+
+	params := PathParameters(s.RESTShow.Path)
+	if len(params) == 0 {
+		return "", false
+	}
+	return params[len(params)-1], true
+}
+
+// lastWord returns the last word of a camelCase, snake_case or kebab-case name,
+// like "Id" for "boardId" or "ID" for "policyID"
+func lastWord(name string) string {
+	if index := strings.LastIndexAny(name, "_-"); index >= 0 {
+		return name[index+1:]
+	}
+
+	runes := []rune(name)
+	start := len(runes) - 1
+	for start > 0 && unicode.IsUpper(runes[start]) == unicode.IsUpper(runes[start-1]) {
+		start--
+	}
+	// A single lowercase word preceded by capital letters, like "Name" in "specName"
+	if start > 0 && !unicode.IsUpper(runes[start]) && unicode.IsUpper(runes[start-1]) {
+		start--
+	}
+	return string(runes[start:])
+}
+
+// matchIdentityAttribute finds the attribute that supplies the value of a path parameter,
+// trying an exact match, a case insensitive match, and finally the last word of the parameter
+// name, so that "boardId" is matched with "id" and "specName" with "Name".
+func matchIdentityAttribute(parameter string, attributes []*Attribute) (string, bool) {
+	candidates := []string{parameter, lastWord(parameter)}
+
+	for _, candidate := range candidates {
+		for _, att := range attributes {
+			if att.Name == candidate {
+				return att.Name, true
+			}
+		}
+		for _, att := range attributes {
+			if strings.EqualFold(att.Name, candidate) {
+				return att.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ProbeForIdentity determines how the resource is identified. An identity established by
+// OpenAPI links or by a binding is preferred. Otherwise, the last path parameter of the
+// show action is matched with an attribute of the show response body.
+func (s *RESTResource) ProbeForIdentity(mediaType string) *RESTIdentity {
+	if s.Identity != nil {
+		return s.Identity
+	}
+
+	parameter, ok := s.identityParameter()
+	if !ok {
+		return nil
+	}
+
+	op := s.GetOperation(s.RESTShow)
+	if op == nil {
+		return nil
+	}
+
+	attMap := make(map[string]*Attribute)
+	extractResponseAttributes(attMap, Show, mediaType, s.Envelope, op)
+
+	attribute, ok := matchIdentityAttribute(parameter, attributeValues(attMap))
+	if !ok {
+		log.Printf("[DEBUG] No identity attribute of %s matches path parameter %s", s.Name, parameter)
+		return nil
+	}
+
+	s.Identity = &RESTIdentity{
+		Parameter: parameter,
+		Attribute: attribute,
+	}
+	return s.Identity
+}
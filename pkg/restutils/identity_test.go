@@ -0,0 +1,109 @@
+package restutils
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func Test_lastWord(t *testing.T) {
+	cases := map[string]string{
+		"boardId":   "Id",
+		"board_id":  "id",
+		"specName":  "Name",
+		"policyID":  "ID",
+		"namespace": "namespace",
+		"job-name":  "name",
+	}
+
+	for before, expected := range cases {
+		actual := lastWord(before)
+		if actual != expected {
+			t.Errorf("expected %s but got %s", expected, actual)
+		}
+	}
+}
+
+func Test_ProbeForIdentity(t *testing.T) {
+	t.Run("restlike boards", func(t *testing.T) {
+		doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/restlike.yaml")
+		if err != nil {
+			t.Fatalf("invalid fixture: %s", err)
+		}
+
+		resource := &RESTResource{
+			probe:    &RESTProbe{Document: doc},
+			Name:     "Boards",
+			RESTShow: &RESTAction{Show, http.MethodGet, "/v3/boards/{board_id}"},
+		}
+
+		identity := resource.ProbeForIdentity("application/json")
+		if identity == nil {
+			t.Fatal("expected identity")
+		}
+
+		if identity.Parameter != "board_id" || identity.Attribute != "id" {
+			t.Errorf("expected board_id identified by id, got %s identified by %s", identity.Parameter, identity.Attribute)
+		}
+
+		attributes := resource.ProbeForAttributes("application/json")
+		for _, att := range attributes {
+			if att.Name == "board_id" {
+				t.Error("expected the identity path parameter to be supplied by the identity attribute")
+			}
+			if att.Name == "id" && !att.Identity {
+				t.Error("expected id to be the identity attribute")
+			}
+		}
+	})
+
+	t.Run("nomad quota", func(t *testing.T) {
+		doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/openapi3/nomad.yaml")
+		if err != nil {
+			t.Fatalf("invalid fixture: %s", err)
+		}
+
+		resource := &RESTResource{
+			probe:    &RESTProbe{Document: doc},
+			Name:     "Quota",
+			RESTShow: &RESTAction{Show, http.MethodGet, "/quota/{specName}"},
+		}
+
+		identity := resource.ProbeForIdentity("application/json")
+		if identity == nil {
+			t.Fatal("expected identity")
+		}
+
+		if identity.Attribute != "Name" {
+			t.Errorf("expected specName to be identified by Name, got %s", identity.Attribute)
+		}
+	})
+
+	t.Run("bound identity is preferred", func(t *testing.T) {
+		doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/restlike.yaml")
+		if err != nil {
+			t.Fatalf("invalid fixture: %s", err)
+		}
+
+		probe := NewProbe(doc)
+		resources, err := probe.BindResources([]RESTBinding{
+			{
+				Name: "Boards",
+				ReadAction: &ActionBinding{
+					Path:   "/v3/boards/{board_id}",
+					Method: "GET",
+				},
+				Identity: "name",
+			},
+		})
+		if err != nil {
+			t.Fatalf("invalid binding: %s", err)
+		}
+
+		identity := resources["Boards"].ProbeForIdentity("application/json")
+		if identity == nil || identity.Attribute != "name" {
+			t.Error("expected the bound identity attribute \"name\"")
+		}
+	})
+}
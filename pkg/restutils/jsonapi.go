@@ -0,0 +1,230 @@
+package restutils
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// JSONAPIMediaType is the media type of JSON:API documents. See https://jsonapi.org
+const JSONAPIMediaType = "application/vnd.api+json"
+
+// RESTJSONAPI describes a resource whose bodies are JSON:API resource objects, which keep
+// their fields in "attributes" and the resources they refer to in "relationships"
+type RESTJSONAPI struct {
+	// Type is the JSON:API type of the resource, like "articles"
+	Type string
+
+	// Relationships are exposed as attributes that hold the IDs of the related resources
+	Relationships []*RESTRelationship
+}
+
+// RESTRelationship describes a relationship of a JSON:API resource
+type RESTRelationship struct {
+	// Name is the name of the relationship, like "author"
+	Name string
+
+	// Attribute is the name of the attribute that holds the related IDs, like "author_id"
+	Attribute string
+
+	// Type is the JSON:API type of the related resources, like "people"
+	Type string
+
+	// Many indicates a to-many relationship, whose attribute is a list of IDs
+	Many bool
+}
+
+// isJSONAPIResource reports whether a schema describes a JSON:API resource object, which has
+// a type and its fields in an "attributes" object
+func isJSONAPIResource(schema *openapi3.Schema) bool {
+	if schema == nil {
+		return false
+	}
+
+	attributes, ok := schema.Properties["attributes"]
+	if !ok || attributes.Value == nil || !isObject(attributes.Value) {
+		return false
+	}
+	_, ok = schema.Properties["type"]
+	return ok
+}
+
+// relationshipAttribute names the attribute of a relationship, like "author_id" for "author"
+// or "tag_ids" for "tags"
+func relationshipAttribute(name string, many bool) string {
+	if !many {
+		return name + "_id"
+	}
+	if strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") {
+		name = strings.TrimSuffix(name, "s")
+	}
+	return name + "_ids"
+}
+
+// relationshipData returns the schema of the resource identifiers of a relationship, and
+// whether it is a to-many relationship
+func relationshipData(relationship *openapi3.Schema) (*openapi3.Schema, bool) {
+	if relationship == nil {
+		return nil, false
+	}
+
+	data, ok := relationship.Properties["data"]
+	if !ok || data.Value == nil {
+		return nil, false
+	}
+	if isArray(data.Value) && data.Value.Items != nil {
+		return data.Value.Items.Value, true
+	}
+	return data.Value, false
+}
+
+// enumType returns the only value of the "type" property of a schema, like "articles"
+func enumType(schema *openapi3.Schema) string {
+	if schema == nil {
+		return ""
+	}
+
+	t, ok := schema.Properties["type"]
+	if !ok || t.Value == nil || len(t.Value.Enum) != 1 {
+		return ""
+	}
+	value, _ := t.Value.Enum[0].(string)
+	return value
+}
+
+// flattenJSONAPI returns the schema of the attributes of a JSON:API resource object, with its
+// id and an attribute for the IDs of each of its relationships. Other schemas are returned as
+// they are.
+func flattenJSONAPI(schema *openapi3.Schema) *openapi3.Schema {
+	if !isJSONAPIResource(schema) {
+		return schema
+	}
+
+	result := openapi3.NewObjectSchema()
+	result.Properties = make(openapi3.Schemas)
+
+	attributes := schema.Properties["attributes"].Value
+	for name, property := range attributes.Properties {
+		result.Properties[name] = property
+	}
+	result.Required = append(result.Required, attributes.Required...)
+
+	if id, ok := schema.Properties["id"]; ok {
+		result.Properties["id"] = id
+	}
+
+	relationships, ok := schema.Properties["relationships"]
+	if !ok || relationships.Value == nil {
+		return result
+	}
+
+	for name, relationship := range relationships.Value.Properties {
+		if relationship.Value == nil {
+			continue
+		}
+		if _, many := relationshipData(relationship.Value); many {
+			ids := openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema())
+			ids.Description = relationship.Value.Description
+			result.Properties[relationshipAttribute(name, true)] = openapi3.NewSchemaRef("", ids)
+		} else {
+			id := openapi3.NewStringSchema()
+			id.Description = relationship.Value.Description
+			result.Properties[relationshipAttribute(name, false)] = openapi3.NewSchemaRef("", id)
+		}
+	}
+	return result
+}
+
+// resourceSchema returns the schema of the resource within a body, which is located by the
+// envelope JSON pointer and flattened if it is a JSON:API resource object
+func resourceSchema(schema *openapi3.Schema, envelope string) *openapi3.Schema {
+	schema = envelopeSchema(schema, envelope)
+	if schema == nil {
+		return nil
+	}
+	return flattenJSONAPI(schema)
+}
+
+// ProbeForJSONAPI determines whether the resource is a JSON:API resource object within its
+// envelope, as documented by the show response body. The type and the types of relationships
+// are taken from single value "type" enums, and otherwise from the last segment of the
+// collection path and the relationship names, which are pluralized for to-one relationships
+// since types are conventionally plural. The result is nil if the resource is not a
+// JSON:API resource object.
+func (s *RESTResource) ProbeForJSONAPI(mediaType string) *RESTJSONAPI {
+	op := s.GetOperation(s.RESTShow)
+	if op == nil {
+		return nil
+	}
+
+	for _, code := range successfulResponseCodes[Show] {
+		response := op.Responses.Get(code)
+		if response == nil || response.Value == nil {
+			continue
+		}
+
+		body := response.Value.Content.Get(mediaType)
+		if body == nil || body.Schema == nil {
+			continue
+		}
+
+		schema := envelopeSchema(body.Schema.Value, s.Envelope)
+		if !isJSONAPIResource(schema) {
+			return nil
+		}
+		return s.jsonAPI(schema)
+	}
+	return nil
+}
+
+// jsonAPI describes the JSON:API resource object schema
+func (s *RESTResource) jsonAPI(schema *openapi3.Schema) *RESTJSONAPI {
+	result := &RESTJSONAPI{
+		Type:          enumType(schema),
+		Relationships: make([]*RESTRelationship, 0),
+	}
+
+	if result.Type == "" {
+		path := s.RESTShow.Path
+		if s.RESTCreate != nil {
+			path = s.RESTCreate.Path
+		} else if s.RESTIndex != nil {
+			path = s.RESTIndex.Path
+		}
+		segments := strings.Split(strings.Trim(path, "/"), "/")
+		for i := len(segments) - 1; i >= 0 && result.Type == ""; i-- {
+			if !strings.HasPrefix(segments[i], "{") {
+				result.Type = segments[i]
+			}
+		}
+	}
+
+	relationships, ok := schema.Properties["relationships"]
+	if !ok || relationships.Value == nil {
+		return result
+	}
+
+	names := make([]string, 0, len(relationships.Value.Properties))
+	for name := range relationships.Value.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		data, many := relationshipData(relationships.Value.Properties[name].Value)
+		relationship := &RESTRelationship{
+			Name:      name,
+			Attribute: relationshipAttribute(name, many),
+			Type:      enumType(data),
+			Many:      many,
+		}
+		if relationship.Type == "" && many {
+			relationship.Type = name
+		} else if relationship.Type == "" {
+			relationship.Type = name + "s"
+		}
+		result.Relationships = append(result.Relationships, relationship)
+	}
+	return result
+}
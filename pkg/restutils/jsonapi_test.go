@@ -0,0 +1,83 @@
+package restutils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func Test_JSONAPI(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/jsonapi.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s", err)
+	}
+
+	probe := NewProbe(doc)
+	articles, ok := probe.ProbeForResources()["Articles"]
+	if !ok {
+		t.Fatal("expected \"Articles\" resource")
+	}
+
+	mediaType := articles.DetermineContentMediaType()
+	if mediaType == nil || *mediaType != JSONAPIMediaType {
+		t.Fatalf("expected the JSON:API media type, got %v", mediaType)
+	}
+
+	articles.Envelope = articles.ProbeForEnvelope(*mediaType)
+	if articles.Envelope != "/data" {
+		t.Fatalf("expected the /data envelope, got %q", articles.Envelope)
+	}
+
+	expected := &RESTJSONAPI{
+		Type: "articles",
+		Relationships: []*RESTRelationship{
+			{Name: "author", Attribute: "author_id", Type: "people"},
+			{Name: "tags", Attribute: "tag_ids", Type: "tags", Many: true},
+		},
+	}
+	if actual := articles.ProbeForJSONAPI(*mediaType); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %#v, got %#v", expected, actual)
+	}
+
+	attributes := make(map[string]*Attribute)
+	for _, att := range articles.ProbeForAttributes(*mediaType) {
+		attributes[att.Name] = att
+	}
+
+	for _, name := range []string{"id", "title", "body", "author_id", "tag_ids"} {
+		if _, ok := attributes[name]; !ok {
+			t.Errorf("expected the flattened attribute %s, got %v", name, attributes)
+		}
+	}
+	for _, name := range []string{"type", "attributes", "relationships", "links"} {
+		if _, ok := attributes[name]; ok {
+			t.Errorf("expected the resource object member %s to be excluded", name)
+		}
+	}
+
+	if tags := attributes["tag_ids"]; tags != nil && (tags.Type != TypeArray || tags.ReadOnly) {
+		t.Errorf("expected tag_ids to be a writable list, got %s", tags)
+	}
+	if identity := articles.ProbeForIdentity(*mediaType); identity == nil || identity.Attribute != "id" {
+		t.Errorf("expected the resource object id to identify articles, got %#v", identity)
+	}
+	if patch := articles.ProbeForPatch(articles.RESTUpdate); patch == nil || patch.Format != MergePatch || patch.MediaType != "" {
+		t.Errorf("expected JSON:API updates to send the changed members of the resource object, got %#v", patch)
+	}
+}
+
+func Test_ProbeForJSONAPI(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/envelope.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s", err)
+	}
+
+	probe := NewProbe(doc)
+	for name, resource := range probe.ProbeForResources() {
+		resource.Envelope = "/data"
+		if result := resource.ProbeForJSONAPI("application/json"); result != nil {
+			t.Errorf("expected %s not to be a JSON:API resource, got %#v", name, result)
+		}
+	}
+}
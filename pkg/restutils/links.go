@@ -0,0 +1,209 @@
+package restutils
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// RESTIdentity describes how a resource is identified: the show action path parameter
+// and the response attribute that supplies its value.
+type RESTIdentity struct {
+	// Parameter is the name of the show action path parameter that identifies the resource
+	Parameter string
+
+	// Attribute is the name of the response body attribute that supplies the parameter
+	Attribute string
+}
+
+const responseBodyExpression = "$response.body#"
+
+// operationByID finds the path and method of the operation with the specified operationId
+func (probe *RESTProbe) operationByID(operationID string) (string, string, bool) {
+	for path, pathItem := range probe.Document.Paths {
+		for method, op := range pathItem.Operations() {
+			if op.OperationID == operationID {
+				return path, method, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// operationByRef resolves a local operationRef, like "#/paths/~1boards~1{boardId}/get",
+// to a path and method.
+func (probe *RESTProbe) operationByRef(ref string) (string, string, bool) {
+	fragment := ref[strings.Index(ref, "#")+1:]
+	tokens := strings.Split(strings.TrimPrefix(fragment, "/"), "/")
+	if len(tokens) != 3 || tokens[0] != "paths" {
+		return "", "", false
+	}
+
+	path := strings.NewReplacer("~1", "/", "~0", "~").Replace(tokens[1])
+	method := strings.ToUpper(tokens[2])
+
+	pathItem := probe.Document.Paths.Find(path)
+	if pathItem == nil || pathItem.GetOperation(method) == nil {
+		return "", "", false
+	}
+	return path, method, true
+}
+
+// resolveLink finds the path and method of the operation targeted by a link
+func (probe *RESTProbe) resolveLink(link *openapi3.Link) (string, string, bool) {
+	if link.OperationID != "" {
+		return probe.operationByID(link.OperationID)
+	}
+	if link.OperationRef != "" {
+		return probe.operationByRef(link.OperationRef)
+	}
+	return "", "", false
+}
+
+// linkIdentity determines the identity described by link parameters that are sourced from
+// a top level attribute of the response body, like "$response.body#/id". The parameter that
+// is the last path parameter of the linked path is preferred, and otherwise the parameters are
+// tried in sorted order.
+func linkIdentity(link *openapi3.Link, path string) *RESTIdentity {
+	parameters := make([]string, 0, len(link.Parameters))
+	for parameter := range link.Parameters {
+		parameters = append(parameters, parameter)
+	}
+	sort.Strings(parameters)
+
+	if pathParameters := PathParameters(path); len(pathParameters) > 0 {
+		last := pathParameters[len(pathParameters)-1]
+		if _, ok := link.Parameters[last]; ok {
+			parameters = append([]string{last}, parameters...)
+		}
+	}
+
+	for _, parameter := range parameters {
+		value := link.Parameters[parameter]
+		expression, ok := value.(string)
+		if !ok || !strings.HasPrefix(expression, responseBodyExpression) {
+			continue
+		}
+
+		pointer := strings.TrimPrefix(strings.TrimPrefix(expression, responseBodyExpression), "/")
+		if pointer == "" || strings.Contains(pointer, "/") {
+			log.Printf("[DEBUG] Link parameter %s uses unsupported expression %s", parameter, expression)
+			continue
+		}
+
+		return &RESTIdentity{
+			Parameter: parameter,
+			Attribute: strings.NewReplacer("~1", "/", "~0", "~").Replace(pointer),
+		}
+	}
+	return nil
+}
+
+// createLinks returns the links declared by the successful responses of the create action
+func (s *RESTResource) createLinks() openapi3.Links {
+	op := s.GetOperation(s.RESTCreate)
+	if op == nil {
+		return nil
+	}
+
+	for _, code := range successfulResponseCodes[Create] {
+		if response := op.Responses.Get(code); response != nil && len(response.Value.Links) > 0 {
+			return response.Value.Links
+		}
+	}
+	return nil
+}
+
+// applyLinks uses the links declared on create responses to pair the linked show, update, and
+// delete operations with the create action, regardless of how the paths were grouped
+// heuristically. Operations claimed by probe rules are never moved.
+func (probe *RESTProbe) applyLinks(result map[string]*RESTResource, claimed map[string]map[string]bool) {
+	for _, resource := range result {
+		if resource.RESTCreate == nil {
+			continue
+		}
+
+		links := resource.createLinks()
+		names := make([]string, 0, len(links))
+		for name := range links {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			link := links[name].Value
+			path, method, ok := probe.resolveLink(link)
+			if !ok {
+				fmt.Printf("warning: link %s of %s could not be resolved\n", name, resource.Name)
+				continue
+			}
+
+			var pseudonym RESTPseudonym
+			switch method {
+			case http.MethodGet:
+				pseudonym = Show
+			case http.MethodPut, http.MethodPatch:
+				pseudonym = Update
+			case http.MethodDelete:
+				pseudonym = Delete
+			default:
+				continue
+			}
+
+			if pseudonym == Show && resource.Identity == nil {
+				resource.Identity = linkIdentity(link, path)
+			}
+
+			if claimed[path][method] {
+				continue
+			}
+
+			probe.moveAction(result, resource, &RESTAction{
+				Name:   pseudonym,
+				Method: method,
+				Path:   path,
+			})
+		}
+	}
+}
+
+// moveAction binds the action to the resource, removing it from any other resource it was
+// bound to. When a show action is moved, the update and delete actions that share its path
+// are moved along with it. Resources that no longer have any actions are removed.
+func (probe *RESTProbe) moveAction(result map[string]*RESTResource, resource *RESTResource, action *RESTAction) {
+	if existing := resource.action(action.Name); existing != nil {
+		if existing.Path == action.Path && existing.Method == action.Method {
+			return
+		}
+		log.Printf("[DEBUG] %s %s operation %s replaced by linked operation %s", resource.Name, action.Name, existing.Path, action.Path)
+	}
+	resource.setAction(action)
+
+	for key, other := range result {
+		if other == resource {
+			continue
+		}
+
+		for _, pseudonym := range []RESTPseudonym{Show, Update, Delete} {
+			bound := other.action(pseudonym)
+			if bound == nil || bound.Path != action.Path {
+				continue
+			}
+
+			if pseudonym == action.Name && bound.Method == action.Method {
+				other.unsetAction(pseudonym)
+			} else if action.Name == Show && pseudonym != Show && resource.action(pseudonym) == nil {
+				resource.setAction(bound)
+				other.unsetAction(pseudonym)
+			}
+		}
+
+		if len(other.Paths()) == 0 {
+			delete(result, key)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package restutils
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func Test_linkIdentity(t *testing.T) {
+	link := &openapi3.Link{
+		Parameters: map[string]interface{}{
+			"workspaceId": "$response.body#/workspace",
+			"boardId":     "$response.body#/id",
+			"ownerId":     "$response.body#/owner",
+			"expand":      "details",
+		},
+	}
+
+	// The last path parameter is preferred over the others, whatever the map order
+	for i := 0; i < 20; i++ {
+		identity := linkIdentity(link, "/workspaces/{workspaceId}/boards/{boardId}")
+		if identity == nil || identity.Parameter != "boardId" || identity.Attribute != "id" {
+			t.Fatalf("expected boardId to be supplied by id, got %#v", identity)
+		}
+	}
+
+	// Otherwise the first parameter in sorted order is used
+	for i := 0; i < 20; i++ {
+		identity := linkIdentity(link, "/boards/{board}")
+		if identity == nil || identity.Parameter != "boardId" {
+			t.Fatalf("expected the first sorted parameter boardId, got %#v", identity)
+		}
+	}
+
+	if identity := linkIdentity(&openapi3.Link{Parameters: map[string]interface{}{"boardId": "$request.path.id"}}, "/boards/{boardId}"); identity != nil {
+		t.Errorf("expected no identity without response body parameters, got %#v", identity)
+	}
+}
@@ -0,0 +1,76 @@
+package restutils
+
+import "fmt"
+
+// AttributeMapping maps an attribute to the property at a JSON pointer within the resource,
+// like "/spec/template/metadata/labels"
+type AttributeMapping struct {
+	Name    string
+	Pointer string
+}
+
+// detach removes the content attribute located by the pointer tokens from the attributes,
+// along with the objects that contained only it. The attributes are not modified. The
+// detached attribute is nil if the tokens do not locate one.
+func detach(attributes []*Attribute, tokens []string) ([]*Attribute, *Attribute) {
+	for i, att := range attributes {
+		if att.Name != tokens[0] || att.In != InContent {
+			continue
+		}
+
+		if len(tokens) == 1 {
+			return remove(attributes, i), att
+		}
+		if att.Type != TypeObject {
+			return attributes, nil
+		}
+
+		nested, found := detach(att.Attributes, tokens[1:])
+		if found == nil {
+			return attributes, nil
+		}
+		if len(nested) == 0 {
+			return remove(attributes, i), found
+		}
+
+		parent := *att
+		parent.Attributes = nested
+		result := append([]*Attribute{}, attributes...)
+		result[i] = &parent
+		return result, found
+	}
+	return attributes, nil
+}
+
+// remove returns the attributes without the one at the index
+func remove(attributes []*Attribute, index int) []*Attribute {
+	result := make([]*Attribute, 0, len(attributes)-1)
+	result = append(result, attributes[:index]...)
+	return append(result, attributes[index+1:]...)
+}
+
+// MapAttributes exposes the properties located by the mappings as attributes with the mapped
+// names, in place of the nested attributes that described them. Objects that contained only
+// mapped properties are removed. It is an error if a mapping does not locate a property of
+// an object, or if its name is already the name of an attribute.
+func MapAttributes(attributes []*Attribute, mappings []AttributeMapping) ([]*Attribute, error) {
+	result := attributes
+	for _, mapping := range mappings {
+		var found *Attribute
+		if result, found = detach(result, pointerTokens(mapping.Pointer)); found == nil {
+			return nil, fmt.Errorf("attribute %s: no property found at %s", mapping.Name, mapping.Pointer)
+		}
+
+		for _, att := range result {
+			if att.Name == mapping.Name {
+				return nil, fmt.Errorf("attribute %s: the name is already used by another attribute", mapping.Name)
+			}
+		}
+
+		mapped := *found
+		mapped.Name = mapping.Name
+		mapped.Pointer = mapping.Pointer
+		result = append(result, &mapped)
+	}
+	return result, nil
+}
@@ -0,0 +1,90 @@
+package restutils
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func Test_MapAttributes(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/mapping.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s", err)
+	}
+
+	probe := NewProbe(doc)
+	deployments, ok := probe.ProbeForResources()["Deployments"]
+	if !ok {
+		t.Fatal("expected \"Deployments\" resource")
+	}
+
+	attributes := deployments.ProbeForAttributes("application/json")
+	mapped, err := MapAttributes(attributes, []AttributeMapping{
+		{Name: "labels", Pointer: "/spec/template/metadata/labels"},
+		{Name: "replicas", Pointer: "/spec/replicas"},
+		{Name: "title", Pointer: "/name"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	byName := make(map[string]*Attribute)
+	for _, att := range mapped {
+		byName[att.Name] = att
+	}
+
+	cases := map[string]string{
+		"labels":   "/spec/template/metadata/labels",
+		"replicas": "/spec/replicas",
+		"title":    "/name",
+	}
+	for name, pointer := range cases {
+		if att, ok := byName[name]; !ok || att.Pointer != pointer {
+			t.Errorf("expected attribute %s to be mapped to %s, got %v", name, pointer, att)
+		}
+	}
+	if labels := byName["labels"]; labels != nil && labels.Description != "The labels of each replica" {
+		t.Errorf("expected the mapped attribute to keep its description, got %q", labels.Description)
+	}
+	if _, ok := byName["name"]; ok {
+		t.Error("expected the renamed attribute to be removed")
+	}
+
+	// The spec keeps the template name, which is not mapped
+	spec := byName["spec"]
+	if spec == nil || len(spec.Attributes) != 1 || spec.Attributes[0].Name != "template" {
+		t.Fatalf("expected the spec to keep only its template, got %v", spec)
+	}
+	metadata := spec.Attributes[0].Attributes[0]
+	if len(metadata.Attributes) != 1 || metadata.Attributes[0].Name != "name" {
+		t.Errorf("expected the metadata to keep only its name, got %v", metadata.Attributes)
+	}
+
+	// The probed attributes are not modified
+	for _, att := range attributes {
+		if att.Name == "spec" && len(att.Attributes) != 2 {
+			t.Errorf("expected the probed spec to be unchanged, got %v", att.Attributes)
+		}
+	}
+
+	mapped, err = MapAttributes(attributes, []AttributeMapping{{Name: "template_name", Pointer: "/spec/template/metadata/name"}, {Name: "labels", Pointer: "/spec/template/metadata/labels"}, {Name: "replicas", Pointer: "/spec/replicas"}})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	for _, att := range mapped {
+		if att.Name == "spec" {
+			t.Errorf("expected the spec to be removed once all of its properties are mapped, got %v", att)
+		}
+	}
+
+	invalid := [][]AttributeMapping{
+		{{Name: "missing", Pointer: "/spec/missing"}},
+		{{Name: "nested", Pointer: "/name/first"}},
+		{{Name: "spec", Pointer: "/spec/replicas"}},
+	}
+	for _, mappings := range invalid {
+		if _, err := MapAttributes(attributes, mappings); err == nil {
+			t.Errorf("expected %v to be invalid", mappings)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package restutils
+
+import (
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Media types of form request bodies
+const (
+	FormMediaType      = "application/x-www-form-urlencoded"
+	MultipartMediaType = "multipart/form-data"
+)
+
+// requestMediaTypes are the request body media types that are selected when the media type
+// of the resource is not accepted, in order of preference
+var requestMediaTypes = []string{"application/json", JSONAPIMediaType, FormMediaType, MultipartMediaType}
+
+// IsFormMediaType reports whether a media type is encoded as form fields rather than JSON
+func IsFormMediaType(mediaType string) bool {
+	return mediaType == FormMediaType || mediaType == MultipartMediaType
+}
+
+// selectMediaType returns the media type of the resource if the content has it, and
+// otherwise the first of the preferred media types the content has. If it has none of
+// them, the first of its media types is selected.
+func selectMediaType(content openapi3.Content, mediaType string, preferred []string) string {
+	if _, ok := content[mediaType]; ok {
+		return mediaType
+	}
+	for _, candidate := range preferred {
+		if _, ok := content[candidate]; ok {
+			return candidate
+		}
+	}
+
+	keys := make([]string, 0, len(content))
+	for key := range content {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if len(keys) > 0 {
+		return keys[0]
+	}
+	return ""
+}
+
+// ProbeForRequestMediaType selects the media type of the request body of the operation bound
+// to the action. The media type of the resource is preferred, then JSON, then form encoded
+// and multipart bodies. The result is empty if the operation has no request body.
+func (s *RESTResource) ProbeForRequestMediaType(action *RESTAction, mediaType string) string {
+	op := s.GetOperation(action)
+	if op == nil || op.RequestBody == nil || op.RequestBody.Value == nil {
+		return ""
+	}
+	return selectMediaType(op.RequestBody.Value.Content, mediaType, requestMediaTypes)
+}
+
+// ProbeForResponseMediaType selects the media type of the successful response bodies of the
+// operation bound to the action. The media type of the resource is preferred, then the well
+// known media types. The result is the media type of the resource if the operation does not
+// document a response body.
+func (s *RESTResource) ProbeForResponseMediaType(action *RESTAction, mediaType string) string {
+	op := s.GetOperation(action)
+	if op == nil {
+		return mediaType
+	}
+
+	for _, code := range successfulResponseCodes[action.Name] {
+		response := op.Responses.Get(code)
+		if response == nil || response.Value == nil || len(response.Value.Content) == 0 {
+			continue
+		}
+		return selectMediaType(response.Value.Content, mediaType, requestMediaTypes[:2])
+	}
+	return mediaType
+}
+
+// ProbeForFiles returns the names of the properties of a multipart request body that are
+// uploaded files, which have the binary format. The result is empty for other request bodies.
+func (s *RESTResource) ProbeForFiles(action *RESTAction, requestMediaType string) []string {
+	result := make([]string, 0)
+
+	op := s.GetOperation(action)
+	if requestMediaType != MultipartMediaType || op == nil || op.RequestBody == nil || op.RequestBody.Value == nil {
+		return result
+	}
+
+	body := op.RequestBody.Value.Content.Get(requestMediaType)
+	if body == nil || body.Schema == nil || body.Schema.Value == nil {
+		return result
+	}
+
+	for name, property := range body.Schema.Value.Properties {
+		if property.Value != nil && property.Value.Type == string(TypeString) && property.Value.Format == string(FormatBinary) {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// requestEnvelope returns the envelope of request bodies of the media type. Form fields are
+// never wrapped in an envelope.
+func (s *RESTResource) requestEnvelope(mediaType string) string {
+	if IsFormMediaType(mediaType) {
+		return ""
+	}
+	return s.Envelope
+}
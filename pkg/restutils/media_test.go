@@ -0,0 +1,58 @@
+package restutils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func Test_FormMediaTypes(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/forms.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s", err)
+	}
+
+	probe := NewProbe(doc)
+	documents, ok := probe.ProbeForResources()["Documents"]
+	if !ok {
+		t.Fatal("expected \"Documents\" resource")
+	}
+
+	mediaType := "application/json"
+	cases := map[*RESTAction]string{
+		documents.RESTCreate: MultipartMediaType,
+		documents.RESTUpdate: FormMediaType,
+		documents.RESTDelete: "",
+	}
+
+	for action, expected := range cases {
+		if actual := documents.ProbeForRequestMediaType(action, mediaType); actual != expected {
+			t.Errorf("expected the %s request media type to be %q, got %q", action.Name, expected, actual)
+		}
+		if actual := documents.ProbeForResponseMediaType(action, mediaType); actual != mediaType {
+			t.Errorf("expected the %s response media type to be %q, got %q", action.Name, mediaType, actual)
+		}
+	}
+
+	if files := documents.ProbeForFiles(documents.RESTCreate, MultipartMediaType); !reflect.DeepEqual(files, []string{"file"}) {
+		t.Errorf("expected the file property to be uploaded, got %v", files)
+	}
+	if files := documents.ProbeForFiles(documents.RESTUpdate, FormMediaType); len(files) > 0 {
+		t.Errorf("expected no files in form encoded bodies, got %v", files)
+	}
+
+	attributes := make(map[string]*Attribute)
+	for _, att := range documents.ProbeForAttributes(mediaType) {
+		attributes[att.Name] = att
+	}
+
+	for _, name := range []string{"id", "title", "labels", "size", "file"} {
+		if _, ok := attributes[name]; !ok {
+			t.Errorf("expected attribute %s, got %v", name, attributes)
+		}
+	}
+	if file := attributes["file"]; file != nil && (file.ReadOnly || file.Format != FormatBinary) {
+		t.Errorf("expected file to be a writable binary attribute, got %s", file)
+	}
+}
@@ -0,0 +1,62 @@
+package restutils
+
+import (
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// SameParameterName reports whether two names of parameters in the same location name the same
+// parameter. Only header names are case insensitive.
+func SameParameterName(in In, name, other string) bool {
+	if in == InHeader {
+		return strings.EqualFold(name, other)
+	}
+	return name == other
+}
+
+// mergeParameters combines the parameters shared by all operations of a path item with the
+// parameters of a particular operation. Operation parameters override path item parameters
+// with the same name and location.
+func mergeParameters(pathItem *openapi3.PathItem, op *openapi3.Operation) openapi3.Parameters {
+	result := make(openapi3.Parameters, 0, len(pathItem.Parameters)+len(op.Parameters))
+
+	for _, paramRef := range pathItem.Parameters {
+		if paramRef.Value == nil {
+			continue
+		}
+		if op.Parameters.GetByInAndName(paramRef.Value.In, paramRef.Value.Name) != nil {
+			continue
+		}
+		result = append(result, paramRef)
+	}
+
+	for _, paramRef := range op.Parameters {
+		if paramRef.Value == nil {
+			continue
+		}
+		result = append(result, paramRef)
+	}
+
+	return result
+}
+
+// GetParameters returns the parameters of the action's operation, including those
+// declared on the path item.
+func (s *RESTResource) GetParameters(action *RESTAction) openapi3.Parameters {
+	if action == nil {
+		return nil
+	}
+
+	pathItem := s.probe.Document.Paths.Find(action.Path)
+	if pathItem == nil {
+		return nil
+	}
+
+	op := pathItem.GetOperation(action.Method)
+	if op == nil {
+		return nil
+	}
+
+	return mergeParameters(pathItem, op)
+}
@@ -0,0 +1,90 @@
+package restutils
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func Test_GetParameters(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/parameters.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s", err)
+	}
+
+	resource := &RESTResource{
+		probe:      &RESTProbe{Document: doc},
+		Name:       "Boards",
+		RESTShow:   &RESTAction{Show, http.MethodGet, "/boards/{boardId}"},
+		RESTCreate: &RESTAction{Create, http.MethodPost, "/boards"},
+		RESTUpdate: &RESTAction{Update, http.MethodPut, "/boards/{boardId}"},
+	}
+
+	t.Run("path item parameters", func(t *testing.T) {
+		parameters := resource.GetParameters(resource.RESTUpdate)
+		if len(parameters) != 1 || parameters[0].Value.Name != "boardId" {
+			t.Fatalf("expected the boardId path item parameter, got %d parameters", len(parameters))
+		}
+	})
+
+	t.Run("operation parameters override path item parameters", func(t *testing.T) {
+		parameters := resource.GetParameters(resource.RESTShow)
+		if len(parameters) != 1 {
+			t.Fatalf("expected 1 parameter, got %d", len(parameters))
+		}
+
+		expected := "The board to fetch"
+		if actual := parameters[0].Value.Description; actual != expected {
+			t.Errorf("expected %s but got %s", expected, actual)
+		}
+	})
+
+	t.Run("composite attributes", func(t *testing.T) {
+		attributes := compositeAttributes(resource, "application/json")
+
+		var found *Attribute
+		for _, att := range attributes {
+			if att.Name == "boardId" {
+				found = att
+			}
+		}
+
+		if found == nil {
+			t.Fatal("expected the boardId path item parameter to be an attribute")
+		}
+
+		if !found.Required {
+			t.Error("expected boardId to be required")
+		}
+	})
+
+	t.Run("update request body attributes", func(t *testing.T) {
+		for _, att := range compositeAttributes(resource, "application/json") {
+			if att.Name == "archived" {
+				return
+			}
+		}
+		t.Error("expected the update request body to be read from the update operation")
+	})
+}
+
+func Test_SameParameterName(t *testing.T) {
+	cases := []struct {
+		in          In
+		name, other string
+		same        bool
+	}{
+		{InHeader, "X-Tenant", "x-tenant", true},
+		{InQuery, "region", "region", true},
+		{InQuery, "region", "Region", false},
+		{InCookie, "session", "Session", false},
+		{InPath, "boardId", "boardid", false},
+	}
+
+	for _, c := range cases {
+		if actual := SameParameterName(c.in, c.name, c.other); actual != c.same {
+			t.Errorf("expected %s parameters %s and %s to be the same %t, got %t", c.in, c.name, c.other, c.same, actual)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package restutils
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Partial update formats
+const (
+	// MergePatch sends the changed attributes as a JSON object, where removed attributes are
+	// null. See RFC 7386.
+	MergePatch = "merge"
+
+	// JSONPatch sends a list of operations that change the attributes. See RFC 6902.
+	JSONPatch = "json"
+)
+
+// Media types of partial update request bodies
+const (
+	MergePatchMediaType = "application/merge-patch+json"
+	JSONPatchMediaType  = "application/json-patch+json"
+)
+
+// RESTPatch describes an update operation that changes only the attributes it is sent
+type RESTPatch struct {
+	// Format is either MergePatch or JSONPatch
+	Format string
+
+	// MediaType is the media type of the request body, or empty if it is the media type of
+	// the resource
+	MediaType string
+}
+
+// ProbeForPatch determines whether the operation bound to the action is a partial update,
+// which is the case if its method is PATCH. The format is determined by the documented
+// request media types: a JSON Patch is only sent if it is the documented format, and otherwise
+// the changed attributes are sent. The result is nil if the operation replaces the resource.
+func (s *RESTResource) ProbeForPatch(action *RESTAction) *RESTPatch {
+	if action == nil || !strings.EqualFold(action.Method, http.MethodPatch) {
+		return nil
+	}
+
+	result := &RESTPatch{Format: MergePatch}
+
+	op := s.GetOperation(action)
+	if op == nil || op.RequestBody == nil || op.RequestBody.Value == nil {
+		return result
+	}
+
+	content := op.RequestBody.Value.Content
+	if _, ok := content[MergePatchMediaType]; ok {
+		result.MediaType = MergePatchMediaType
+	} else if _, ok := content[JSONPatchMediaType]; ok {
+		result.Format = JSONPatch
+		result.MediaType = JSONPatchMediaType
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+package restutils
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func Test_ProbeForPatch(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/patch.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s", err)
+	}
+
+	resource := &RESTResource{probe: &RESTProbe{Document: doc}}
+
+	cases := map[string]RESTPatch{
+		"/notes/{noteId}":   {Format: MergePatch, MediaType: MergePatchMediaType},
+		"/tags/{tagId}":     {Format: JSONPatch, MediaType: JSONPatchMediaType},
+		"/labels/{labelId}": {Format: MergePatch},
+	}
+
+	for path, expected := range cases {
+		actual := resource.ProbeForPatch(&RESTAction{Update, http.MethodPatch, path})
+		if actual == nil || *actual != expected {
+			t.Errorf("expected PATCH %s to be %#v, got %#v", path, expected, actual)
+		}
+	}
+
+	if actual := resource.ProbeForPatch(&RESTAction{Update, http.MethodPut, "/notes/{noteId}"}); actual != nil {
+		t.Errorf("expected PUT to replace the resource, got %#v", actual)
+	}
+}
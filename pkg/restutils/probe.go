@@ -33,21 +33,32 @@ const (
 	Delete RESTPseudonym = "delete"
 )
 
+// successfulResponseCodes are ordered by preference. 202 Accepted is last, since the body of
+// an asynchronous response often describes the progress of the operation rather than the
+// resource.
 var successfulResponseCodes = map[RESTPseudonym][]int{
-	Create: {201, 200},
+	Create: {201, 200, 202},
 	Show:   {200, 203},
 	Index:  {200, 203},
-	Update: {200},
-	Delete: {200, 204},
+	Update: {200, 202},
+	Delete: {200, 204, 202},
 }
 
 var wellKnownContentTypes = map[string]interface{}{
 	"application/json": nil,
+	JSONAPIMediaType:   nil,
 }
 
 // RESTProbe is the root level type for probing OpenAPI specifications
 type RESTProbe struct {
 	Document *openapi3.T
+
+	// Rules are applied before heuristics when probing for resources
+	Rules []ProbeRule
+
+	// Prefix is removed from each path before deriving resource names. When nil, the path
+	// segments that all paths have in common are used.
+	Prefix *string
 }
 
 // RESTAction is the binding between a REST pseudonym, a method, and a path.
@@ -68,6 +79,17 @@ type RESTResource struct {
 	RESTUpdate *RESTAction
 	RESTDelete *RESTAction
 
+	// Identity describes how the resource is identified, if known
+	Identity *RESTIdentity
+
+	// Parameters are the query, header, or cookie parameters that are exposed as attributes.
+	// Path parameters are always exposed.
+	Parameters []ParameterBinding
+
+	// Envelope is a JSON pointer to the resource within request and response bodies, like
+	// "/data". If empty, the bodies are the resource.
+	Envelope string
+
 	probe *RESTProbe
 }
 
@@ -165,6 +187,10 @@ type Attribute struct {
 	// Name is the key name of the attribute
 	Name string
 
+	// In describes where the attribute is bound: a path, query, header, or cookie
+	// parameter, or the content body.
+	In In
+
 	// Type is the [OpenAPI data type](https://swagger.io/specification/#data-types).
 	// The possible values are integer, number, string, boolean, object, array
 	Type OASType
@@ -184,6 +210,9 @@ type Attribute struct {
 	// parameter or required object schema.
 	Required bool
 
+	// Identity indicates whether this attribute identifies the resource
+	Identity bool
+
 	// Description is the OpenAPI description of the attribute.
 	Description string
 
@@ -192,6 +221,10 @@ type Attribute struct {
 
 	// Schema is a pointer to the full OpenAPI schema for the attribute
 	Schema *openapi3.Schema
+
+	// Pointer locates the property within the resource if the attribute is mapped to a nested
+	// property. It is empty if the attribute is the property named Name.
+	Pointer string
 }
 
 // String is a display string for the attribute
@@ -253,34 +286,41 @@ func (s *RESTResource) ProbeForAttributes(mediaType string) []*Attribute {
 }
 
 // ProbeForResources examines an openapi3 document, pairing related paths together that can
-// potentially represent a CRUD resource.
+// potentially represent a CRUD resource. Operations matched by the probe rules are bound first.
 func (probe *RESTProbe) ProbeForResources() map[string]*RESTResource {
 	doc := probe.Document
 	result := make(map[string]*RESTResource)
 
-	paths := make([]string, 0, len(doc.Paths))
-	for k := range doc.Paths {
-		paths = append(paths, k)
-	}
+	claimed := probe.applyRules(result)
 
-	prefix := naming.FindPrefix(paths)
+	var prefix string
+	if probe.Prefix != nil {
+		prefix = *probe.Prefix
+	} else {
+		paths := make([]string, 0, len(doc.Paths))
+		for k := range doc.Paths {
+			paths = append(paths, k)
+		}
+		prefix = naming.FindPathPrefix(paths)
+	}
 
 	for path, pathItem := range doc.Paths {
-		keyName := makeKeyNameFromPath(path[len(prefix):])
-		if keyName == "" {
-			keyName = makeKeyNameFromPath(path)
+		// Skip paths whose operations were all claimed by rules
+		pathItem = unclaimedOperations(pathItem, claimed[path])
+		if len(claimed[path]) > 0 && len(pathItem.Operations()) == 0 {
+			continue
 		}
 
-		resource, ok := result[keyName]
+		keyName, ok := probe.nameForPath(path)
 		if !ok {
-			resource = &RESTResource{
-				Name:  keyName,
-				probe: probe,
+			keyName = makeKeyNameFromPath(strings.TrimPrefix(path, prefix))
+			if keyName == "" {
+				keyName = makeKeyNameFromPath(path)
 			}
-
-			result[keyName] = resource
 		}
 
+		resource := probe.resourceNamed(result, keyName)
+
 		// Each path can have multiple actions assigned to it, a composite of which could be used as a RESTful set.
 		showOK, showOperation := probeBuildAction(true, resource, resource.RESTShow, Show, path, pathItem, []string{http.MethodGet})
 		if showOK {
@@ -304,9 +344,75 @@ func (probe *RESTProbe) ProbeForResources() map[string]*RESTResource {
 		}
 	}
 
+	// Links explicitly describe which operations belong together, so they are preferred
+	// over the heuristic pairing.
+	probe.applyLinks(result, claimed)
+
 	return result
 }
 
+// resourceNamed finds or creates the resource with the specified name
+func (probe *RESTProbe) resourceNamed(result map[string]*RESTResource, name string) *RESTResource {
+	resource, ok := result[name]
+	if !ok {
+		resource = &RESTResource{
+			Name:  name,
+			probe: probe,
+		}
+		result[name] = resource
+	}
+	return resource
+}
+
+// action returns the action bound to the specified REST pseudonym
+func (s *RESTResource) action(name RESTPseudonym) *RESTAction {
+	switch name {
+	case Create:
+		return s.RESTCreate
+	case Show:
+		return s.RESTShow
+	case Index:
+		return s.RESTIndex
+	case Update:
+		return s.RESTUpdate
+	case Delete:
+		return s.RESTDelete
+	}
+	return nil
+}
+
+// unsetAction removes the action bound to the specified REST pseudonym
+func (s *RESTResource) unsetAction(name RESTPseudonym) {
+	switch name {
+	case Create:
+		s.RESTCreate = nil
+	case Show:
+		s.RESTShow = nil
+	case Index:
+		s.RESTIndex = nil
+	case Update:
+		s.RESTUpdate = nil
+	case Delete:
+		s.RESTDelete = nil
+	}
+}
+
+// setAction binds the action to the resource using its REST pseudonym
+func (s *RESTResource) setAction(action *RESTAction) {
+	switch action.Name {
+	case Create:
+		s.RESTCreate = action
+	case Show:
+		s.RESTShow = action
+	case Index:
+		s.RESTIndex = action
+	case Update:
+		s.RESTUpdate = action
+	case Delete:
+		s.RESTDelete = action
+	}
+}
+
 // DetermineContentMediaType tries to resolve the shared media type used by
 // a RESTResource. At this time, it only probes well known media types
 // on the Show action.
@@ -1,6 +1,7 @@
 package restutils
 
 import (
+	"regexp"
 	"strings"
 	"testing"
 
@@ -107,3 +108,165 @@ func Test_ProbeForResources(t *testing.T) {
 		})
 	})
 }
+
+func Test_ProbeForResourcesWithRules(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/restlike.yaml")
+
+	if err != nil {
+		t.Fatalf("invalid fixture: %s\n", err)
+	}
+
+	probe := NewProbe(doc)
+	probe.Rules = []ProbeRule{
+		{
+			// Heuristics would consider this a collection
+			Pattern: regexp.MustCompile(`^/v3/(search)$`),
+			Name:    "Board${1}",
+			Action:  Show,
+		},
+		{
+			Pattern: regexp.MustCompile(`^/v3/boards`),
+			Name:    "ImageBoards",
+		},
+	}
+	resources := probe.ProbeForResources()
+
+	t.Run("finds two resources", func(t *testing.T) {
+		expected := 2
+		actual := len(resources)
+
+		if expected != actual {
+			t.Errorf("expected %d but got %d", expected, actual)
+		}
+	})
+
+	t.Run("ImageBoards SpecResource", func(t *testing.T) {
+		imageBoards, ok := resources["ImageBoards"]
+		if !ok {
+			t.Fatal("expected resources to contain \"ImageBoards\"")
+		}
+
+		if !imageBoards.IsCRUD() {
+			t.Error("expected \"ImageBoards\" to be a CRUD resource")
+		}
+	})
+
+	t.Run("Boardsearch SpecResource", func(t *testing.T) {
+		search, ok := resources["Boardsearch"]
+		if !ok {
+			t.Fatal("expected resources to contain \"Boardsearch\"")
+		}
+
+		if search.RESTShow == nil || search.RESTShow.Path != "/v3/search" {
+			t.Error("expected \"Boardsearch\" show action to be bound to /v3/search")
+		}
+
+		if search.CanReadCollection() {
+			t.Error("expected \"Boardsearch\" not to have an index action")
+		}
+	})
+}
+
+func Test_ProbeForResourcesWithRulesIsStable(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/patch.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s\n", err)
+	}
+
+	// Each collection matches, so the first path in sorted order claims the create action
+	for i := 0; i < 20; i++ {
+		probe := NewProbe(doc)
+		probe.Rules = []ProbeRule{
+			{
+				Pattern: regexp.MustCompile(`^/(notes|tags|labels)$`),
+				Name:    "Items",
+				Action:  Create,
+			},
+		}
+
+		items, ok := probe.ProbeForResources()["Items"]
+		if !ok {
+			t.Fatal("expected resources to contain \"Items\"")
+		}
+		if items.RESTCreate == nil || items.RESTCreate.Path != "/labels" {
+			t.Fatalf("expected \"Items\" create action to be bound to /labels, got %v", items.RESTCreate)
+		}
+	}
+}
+
+func Test_ProbeForResourcesWithLinks(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/links.yaml")
+
+	if err != nil {
+		t.Fatalf("invalid fixture: %s\n", err)
+	}
+
+	probe := NewProbe(doc)
+	resources := probe.ProbeForResources()
+
+	t.Run("finds one resource", func(t *testing.T) {
+		expected := 1
+		actual := len(resources)
+
+		if expected != actual {
+			t.Errorf("expected %d but got %d", expected, actual)
+		}
+	})
+
+	boards, ok := resources["Boards"]
+	if !ok {
+		t.Fatal("expected resources to contain \"Boards\"")
+	}
+
+	t.Run("binds linked operations", func(t *testing.T) {
+		if !boards.IsCRUD() || !boards.CanUpdate() {
+			t.Fatal("expected \"Boards\" to be a CRUD resource")
+		}
+
+		expected := "/v1/board/{boardId}"
+		for _, action := range []*RESTAction{boards.RESTShow, boards.RESTUpdate, boards.RESTDelete} {
+			if action.Path != expected {
+				t.Errorf("expected %s action path %s but got %s", action.Name, expected, action.Path)
+			}
+		}
+	})
+
+	t.Run("identity", func(t *testing.T) {
+		if boards.Identity == nil {
+			t.Fatal("expected \"Boards\" identity to be determined by links")
+		}
+
+		if boards.Identity.Parameter != "boardId" {
+			t.Errorf("expected identity parameter boardId but got %s", boards.Identity.Parameter)
+		}
+
+		if boards.Identity.Attribute != "id" {
+			t.Errorf("expected identity attribute id but got %s", boards.Identity.Attribute)
+		}
+	})
+}
+
+func Test_ProbeForResourcesWithPrefix(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/restlike.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s\n", err)
+	}
+
+	t.Run("detected", func(t *testing.T) {
+		probe := NewProbe(doc)
+		if _, ok := probe.ProbeForResources()["Boards"]; !ok {
+			t.Error("expected the common /v3/ prefix to be removed")
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		probe := NewProbe(doc)
+		prefix := ""
+		probe.Prefix = &prefix
+
+		resources := probe.ProbeForResources()
+		if _, ok := resources["Boards"]; ok {
+			t.Error("expected no prefix to be removed")
+		}
+	})
+}
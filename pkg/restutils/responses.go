@@ -0,0 +1,70 @@
+package restutils
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TransientStatusCodes are the response status codes that indicate a request may succeed if
+// it is retried
+var TransientStatusCodes = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// matchesStatusCode reports whether a response key, like "503" or "5XX", matches a status code
+func matchesStatusCode(key string, code int) bool {
+	if len(key) == 3 && strings.HasSuffix(strings.ToUpper(key), "XX") {
+		return key[0] == strconv.Itoa(code)[0]
+	}
+	return key == strconv.Itoa(code)
+}
+
+// GetRetryStatusCodes returns the transient status codes documented by the operation bound to
+// the action. Range keys like "5XX" match each transient status code in the range.
+func (s *RESTResource) GetRetryStatusCodes(action *RESTAction) []int {
+	result := make([]int, 0, len(TransientStatusCodes))
+
+	op := s.GetOperation(action)
+	if op == nil {
+		return result
+	}
+
+	for _, code := range TransientStatusCodes {
+		for key := range op.Responses {
+			if matchesStatusCode(key, code) {
+				result = append(result, code)
+				break
+			}
+		}
+	}
+
+	sort.Ints(result)
+	return result
+}
+
+// NotFoundStatusCodes are the response status codes that indicate a resource does not exist
+var NotFoundStatusCodes = []int{http.StatusNotFound, http.StatusGone}
+
+// GetNotFoundStatusCodes returns the not found status codes documented by the operation bound
+// to the action. Range keys like "4XX" are too broad to match.
+func (s *RESTResource) GetNotFoundStatusCodes(action *RESTAction) []int {
+	result := make([]int, 0, len(NotFoundStatusCodes))
+
+	op := s.GetOperation(action)
+	if op == nil {
+		return result
+	}
+
+	for _, code := range NotFoundStatusCodes {
+		if op.Responses.Get(code) != nil {
+			result = append(result, code)
+		}
+	}
+	return result
+}
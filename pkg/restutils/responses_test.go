@@ -0,0 +1,61 @@
+package restutils
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func Test_GetRetryStatusCodes(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/security.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s", err)
+	}
+
+	resource := &RESTResource{
+		probe:      &RESTProbe{Document: doc},
+		Name:       "Widgets",
+		RESTCreate: &RESTAction{Create, http.MethodPost, "/widgets"},
+		RESTShow:   &RESTAction{Show, http.MethodGet, "/widgets/{widgetId}"},
+		RESTUpdate: &RESTAction{Update, http.MethodPut, "/widgets/{widgetId}"},
+	}
+
+	cases := map[*RESTAction][]int{
+		resource.RESTCreate: {},
+		resource.RESTShow:   {502, 503, 504},
+		resource.RESTUpdate: {429},
+	}
+
+	for action, expected := range cases {
+		if actual := resource.GetRetryStatusCodes(action); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected %s %s to retry %v, got %v", action.Method, action.Path, expected, actual)
+		}
+	}
+}
+
+func Test_GetNotFoundStatusCodes(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/security.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s", err)
+	}
+
+	resource := &RESTResource{
+		probe:      &RESTProbe{Document: doc},
+		Name:       "Widgets",
+		RESTShow:   &RESTAction{Show, http.MethodGet, "/widgets/{widgetId}"},
+		RESTDelete: &RESTAction{Delete, http.MethodDelete, "/widgets/{widgetId}"},
+	}
+
+	cases := map[*RESTAction][]int{
+		resource.RESTShow:   {404},
+		resource.RESTDelete: {},
+	}
+
+	for action, expected := range cases {
+		if actual := resource.GetNotFoundStatusCodes(action); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected %s %s to document %v, got %v", action.Method, action.Path, expected, actual)
+		}
+	}
+}
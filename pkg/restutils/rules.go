@@ -0,0 +1,152 @@
+package restutils
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ProbeRule explicitly assigns the operations of paths matching a regular expression to a
+// named resource. Rules are applied before any heuristics are used to pair paths together.
+type ProbeRule struct {
+	// Pattern is matched against each path in the document
+	Pattern *regexp.Regexp
+
+	// Name is the resource name. It may refer to capture groups in the pattern, like "${1}"
+	Name string
+
+	// Action is the REST pseudonym to assign the matching operation to. If empty, the
+	// rule only names the resource and the action is determined heuristically.
+	Action RESTPseudonym
+
+	// Method restricts the rule to a particular HTTP method. If empty, the methods
+	// conventionally associated with the action are tried.
+	Method string
+}
+
+var pseudonymMethods = map[RESTPseudonym][]string{
+	Create: {http.MethodPost},
+	Show:   {http.MethodGet},
+	Index:  {http.MethodGet},
+	Update: {http.MethodPut, http.MethodPatch, http.MethodPost},
+	Delete: {http.MethodDelete},
+}
+
+// RESTPseudonymFromString parses a REST pseudonym, also accepting "read" as an alias of show
+func RESTPseudonymFromString(s string) (RESTPseudonym, error) {
+	switch strings.ToLower(s) {
+	case string(Create):
+		return Create, nil
+	case string(Show), "read":
+		return Show, nil
+	case string(Index), "list":
+		return Index, nil
+	case string(Update):
+		return Update, nil
+	case string(Delete):
+		return Delete, nil
+	default:
+		return "", fmt.Errorf("invalid REST action \"%s\"", s)
+	}
+}
+
+// match returns the expanded resource name if the rule matches the specified path
+func (r *ProbeRule) match(path string) (string, bool) {
+	submatches := r.Pattern.FindStringSubmatchIndex(path)
+	if submatches == nil {
+		return "", false
+	}
+	return string(r.Pattern.ExpandString(nil, r.Name, path, submatches)), true
+}
+
+func (r *ProbeRule) methods() []string {
+	if r.Method != "" {
+		return []string{strings.ToUpper(r.Method)}
+	}
+	return pseudonymMethods[r.Action]
+}
+
+// nameForPath returns the resource name of the first rule that matches the path
+// without specifying an action.
+func (probe *RESTProbe) nameForPath(path string) (string, bool) {
+	for _, rule := range probe.Rules {
+		if rule.Action != "" {
+			continue
+		}
+		if name, ok := rule.match(path); ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// applyRules binds every operation claimed by a rule with an action to its named resource. Rules
+// are applied in order, and each rule is matched against the paths in sorted order, so that the
+// same operations are claimed on every run. The result describes which methods were claimed for
+// each path.
+func (probe *RESTProbe) applyRules(result map[string]*RESTResource) map[string]map[string]bool {
+	claimed := make(map[string]map[string]bool)
+
+	paths := make([]string, 0, len(probe.Document.Paths))
+	for path := range probe.Document.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, rule := range probe.Rules {
+		if rule.Action == "" {
+			continue
+		}
+
+		for _, path := range paths {
+			pathItem := probe.Document.Paths[path]
+			name, ok := rule.match(path)
+			if !ok {
+				continue
+			}
+
+			for _, method := range rule.methods() {
+				if claimed[path][method] || pathItem.GetOperation(method) == nil {
+					continue
+				}
+
+				resource := probe.resourceNamed(result, name)
+				if existing := resource.action(rule.Action); existing != nil {
+					fmt.Printf("warning: %s already has a %s operation defined at %s\n", resource.Name, rule.Action, existing.Path)
+					break
+				}
+
+				resource.setAction(&RESTAction{
+					Name:   rule.Action,
+					Method: method,
+					Path:   path,
+				})
+
+				if claimed[path] == nil {
+					claimed[path] = make(map[string]bool)
+				}
+				claimed[path][method] = true
+				break
+			}
+		}
+	}
+
+	return claimed
+}
+
+// unclaimedOperations returns a copy of the path item without the operations claimed by rules
+func unclaimedOperations(pathItem *openapi3.PathItem, claimed map[string]bool) *openapi3.PathItem {
+	if len(claimed) == 0 {
+		return pathItem
+	}
+
+	result := *pathItem
+	for method := range claimed {
+		result.SetOperation(method, nil)
+	}
+	return &result
+}
@@ -0,0 +1,15 @@
+package restutils
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+// GetSecurity returns the alternative security requirements of the operation bound to the
+// action, each of which maps security scheme names to required scopes. An operation without
+// its own requirements has those of the document. An empty result means that the operation
+// requires no credentials.
+func (s *RESTResource) GetSecurity(action *RESTAction) openapi3.SecurityRequirements {
+	op := s.GetOperation(action)
+	if op != nil && op.Security != nil {
+		return *op.Security
+	}
+	return s.probe.Document.Security
+}
@@ -0,0 +1,59 @@
+package restutils
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func Test_GetSecurity(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../test-fixtures/security.yaml")
+	if err != nil {
+		t.Fatalf("invalid fixture: %s", err)
+	}
+
+	resource := &RESTResource{
+		probe:      &RESTProbe{Document: doc},
+		Name:       "Widgets",
+		RESTIndex:  &RESTAction{Index, http.MethodGet, "/widgets"},
+		RESTCreate: &RESTAction{Create, http.MethodPost, "/widgets"},
+		RESTShow:   &RESTAction{Show, http.MethodGet, "/widgets/{widgetId}"},
+		RESTDelete: &RESTAction{Delete, http.MethodDelete, "/widgets/{widgetId}"},
+	}
+
+	t.Run("document requirements", func(t *testing.T) {
+		security := resource.GetSecurity(resource.RESTShow)
+		if len(security) != 1 {
+			t.Fatalf("expected 1 requirement, got %d", len(security))
+		}
+		if _, ok := security[0]["BearerAuth"]; !ok {
+			t.Errorf("expected the BearerAuth requirement, got %v", security[0])
+		}
+	})
+
+	t.Run("no requirements", func(t *testing.T) {
+		if security := resource.GetSecurity(resource.RESTIndex); len(security) != 0 {
+			t.Errorf("expected no requirements, got %v", security)
+		}
+	})
+
+	t.Run("alternative requirements with scopes", func(t *testing.T) {
+		security := resource.GetSecurity(resource.RESTCreate)
+		if len(security) != 2 {
+			t.Fatalf("expected 2 requirements, got %d", len(security))
+		}
+
+		scopes, ok := security[0]["OAuth2"]
+		if !ok || len(scopes) != 1 || scopes[0] != "widgets:write" {
+			t.Errorf("expected the OAuth2 requirement with the widgets:write scope, got %v", security[0])
+		}
+	})
+
+	t.Run("combined requirements", func(t *testing.T) {
+		security := resource.GetSecurity(resource.RESTDelete)
+		if len(security) != 1 || len(security[0]) != 2 {
+			t.Errorf("expected a single requirement of two schemes, got %v", security)
+		}
+	})
+}